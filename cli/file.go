@@ -7,7 +7,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"syscall"
+
+	"ekyu.moe/util/number"
 )
 
 var (
@@ -203,6 +206,22 @@ func PromptOverrideCreate(filename string) (*Output, error) {
 // When an error is encountered, the currently parsed filenames will be returned.
 // If ignoreInvalid is true, error will always be nil.
 func ParseFileList(args []string, ignoreInvalid bool) ([]string, error) {
+	return parseFileList(args, ignoreInvalid, nil)
+}
+
+// Same as ParseFileList, but the matches of each glob are sorted in natural
+// order instead of lexical order, e.g. "img2.png" before "img10.png". If fold
+// is true, the case is ignored as well. See number.NaturalCompare for details.
+// The order of args is kept.
+func ParseFileListNatural(args []string, ignoreInvalid, fold bool) ([]string, error) {
+	compare := number.NaturalCompare
+	if fold {
+		compare = number.NaturalCompareFold
+	}
+	return parseFileList(args, ignoreInvalid, compare)
+}
+
+func parseFileList(args []string, ignoreInvalid bool, compare func(a, b string) int) ([]string, error) {
 	if len(args) == 0 {
 		return []string{"-"}, nil
 	}
@@ -222,6 +241,11 @@ func ParseFileList(args []string, ignoreInvalid bool) ([]string, error) {
 			continue
 		}
 
+		if compare != nil {
+			sort.Slice(matches, func(i, j int) bool {
+				return compare(matches[i], matches[j]) < 0
+			})
+		}
 		filelist = append(filelist, matches...)
 	}
 
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"strconv"
 )
 
 // ClearTerminal clears the terminal screen and move cursor to top-left corner.
@@ -106,3 +107,23 @@ func NewTTYReader() (*os.File, error) {
 func NewTTYWriter() (*os.File, error) {
 	return newTTYWriter()
 }
+
+// TerminalSize returns the width and height in characters of the terminal that
+// stdout is attached to.
+func TerminalSize() (width, height int, err error) {
+	return terminalSize()
+}
+
+// TerminalWidth returns the width of the terminal that stdout is attached to.
+// If it cannot be determined, e.g. stdout is redirected, it falls back to the
+// COLUMNS environment variable, and finally to 80.
+func TerminalWidth() int {
+	if w, _, err := TerminalSize(); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+
+	return 80
+}
@@ -0,0 +1,29 @@
+// +build linux darwin freebsd netbsd dragonfly
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	Row, Col       uint16
+	Xpixel, Ypixel uint16
+}
+
+func terminalSize() (int, int, error) {
+	ws := winsize{}
+	_, _, e := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		os.Stdout.Fd(),
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&ws)),
+	)
+	if e != 0 {
+		return 0, 0, e
+	}
+
+	return int(ws.Col), int(ws.Row), nil
+}
@@ -0,0 +1,11 @@
+// +build !linux,!darwin,!freebsd,!netbsd,!dragonfly,!windows
+
+package cli
+
+import (
+	"errors"
+)
+
+func terminalSize() (int, int, error) {
+	return 0, 0, errors.New("terminal size is not supported on this platform")
+}
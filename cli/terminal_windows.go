@@ -46,6 +46,17 @@ func clearTerminal() error {
 	return nil
 }
 
+func terminalSize() (int, int, error) {
+	csbi, err := GetConsoleScreenBufferInfo(syscall.Stdout)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	width := int(csbi.Window.Right-csbi.Window.Left) + 1
+	height := int(csbi.Window.Bottom-csbi.Window.Top) + 1
+	return width, height, nil
+}
+
 func newTTYReader() (*os.File, error) {
 	sa := syscall.SecurityAttributes{}
 	sa.Length = uint32(unsafe.Sizeof(sa))
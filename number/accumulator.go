@@ -0,0 +1,224 @@
+package number
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// kahan is a Kahan compensated summation.
+type kahan struct {
+	sum float64
+	c   float64 // compensation, the negative of the lost low-order bits
+}
+
+func (k *kahan) add(x float64) {
+	y := x - k.c
+	t := k.sum + y
+	if math.IsInf(t, 0) || math.IsNaN(t) {
+		// the compensation would be NaN, and then turn an Inf sum into NaN
+		// at the next add, while the sum stays Inf or NaN anyway
+		k.c = 0
+	} else {
+		k.c = (t - k.sum) - y
+	}
+	k.sum = t
+}
+
+func (k *kahan) value() float64 {
+	// an Inf or NaN sum, e.g. from a decoded state, ignores the compensation
+	if math.IsInf(k.sum, 0) || math.IsNaN(k.sum) {
+		return k.sum
+	}
+	return k.sum - k.c
+}
+
+func (k *kahan) merge(o kahan) {
+	k.add(o.sum)
+	k.add(-o.c)
+}
+
+// Accumulator computes statistics of a stream of samples without holding them
+// in memory. The zero value is an empty Accumulator ready to use.
+//
+// The mean and the variance are updated with Welford's online algorithm, and
+// the sum with Kahan compensated summation, so they stay accurate after
+// millions of samples. NaN samples propagate to Sum, Mean and Variance, but
+// are ignored by Min and Max.
+//
+// An Accumulator is not safe for concurrent use. For parallel reductions, feed
+// one Accumulator per goroutine and Merge them afterwards.
+type Accumulator struct {
+	count      int
+	sum        kahan
+	mean, m2   float64
+	min, max   float64
+	minI, maxI int
+	seen       bool // whether min and max are valid
+}
+
+// Add adds a sample x.
+func (a *Accumulator) Add(x float64) {
+	i := a.count
+	a.count++
+	a.sum.add(x)
+
+	d := x - a.mean
+	a.mean += d / float64(a.count)
+	a.m2 += d * (x - a.mean)
+
+	if math.IsNaN(x) {
+		return
+	}
+	if !a.seen {
+		a.min, a.max, a.minI, a.maxI, a.seen = x, x, i, i, true
+		return
+	}
+	if x < a.min {
+		a.min, a.minI = x, i
+	}
+	if x > a.max {
+		a.max, a.maxI = x, i
+	}
+}
+
+// Merge adds all the samples of o to a, as if they were added after the
+// samples of a, so the indices of o's Min and Max are shifted by a.Count().
+func (a *Accumulator) Merge(o *Accumulator) {
+	if o.count == 0 {
+		return
+	}
+	if a.count == 0 {
+		*a = *o
+		return
+	}
+
+	na, nb := float64(a.count), float64(o.count)
+	n := na + nb
+	d := o.mean - a.mean
+	a.mean += d * nb / n
+	a.m2 += o.m2 + d*d*na*nb/n
+	a.sum.merge(o.sum)
+
+	if o.seen {
+		if !a.seen || o.min < a.min {
+			a.min, a.minI = o.min, o.minI+a.count
+		}
+		if !a.seen || o.max > a.max {
+			a.max, a.maxI = o.max, o.maxI+a.count
+		}
+		a.seen = true
+	}
+
+	a.count += o.count
+}
+
+// Reset makes a empty.
+func (a *Accumulator) Reset() {
+	*a = Accumulator{}
+}
+
+// Count returns the number of samples.
+func (a *Accumulator) Count() int {
+	return a.count
+}
+
+// Sum returns the sum of the samples.
+func (a *Accumulator) Sum() float64 {
+	return a.sum.value()
+}
+
+// Mean returns the arithmetic mean of the samples, or NaN if there is none.
+func (a *Accumulator) Mean() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.mean
+}
+
+// Variance returns the variance of the samples with ddof delta degrees of
+// freedom, like Variance. The result is NaN if Count() <= ddof.
+func (a *Accumulator) Variance(ddof int) float64 {
+	if a.count <= ddof {
+		return math.NaN()
+	}
+	return a.m2 / float64(a.count-ddof)
+}
+
+// StdDev returns the square root of Variance(ddof).
+func (a *Accumulator) StdDev(ddof int) float64 {
+	return math.Sqrt(a.Variance(ddof))
+}
+
+// Max returns the index and the value of the largest sample, like MaxN. ok is
+// false if there is no sample other than NaN.
+func (a *Accumulator) Max() (i int, v float64, ok bool) {
+	if !a.seen {
+		return -1, math.NaN(), false
+	}
+	return a.maxI, a.max, true
+}
+
+// Min returns the index and the value of the smallest sample, like MinN. ok is
+// false if there is no sample other than NaN.
+func (a *Accumulator) Min() (i int, v float64, ok bool) {
+	if !a.seen {
+		return -1, math.NaN(), false
+	}
+	return a.minI, a.min, true
+}
+
+type accumulatorJSON struct {
+	Count           int     `json:"count"`
+	Sum             float64 `json:"sum"`
+	SumCompensation float64 `json:"sumCompensation"`
+	Mean            float64 `json:"mean"`
+	M2              float64 `json:"m2"`
+	Min             float64 `json:"min"`
+	MinIndex        int     `json:"minIndex"`
+	Max             float64 `json:"max"`
+	MaxIndex        int     `json:"maxIndex"`
+}
+
+// MarshalJSON implements json.Marshaler. The encoded state is complete, so an
+// Accumulator can be shipped elsewhere, decoded and merged. It fails if a NaN
+// or ±Inf sample has been added.
+func (a Accumulator) MarshalJSON() ([]byte, error) {
+	j := accumulatorJSON{
+		Count:           a.count,
+		Sum:             a.sum.sum,
+		SumCompensation: a.sum.c,
+		Mean:            a.mean,
+		M2:              a.m2,
+		Min:             a.min,
+		MinIndex:        a.minI,
+		Max:             a.max,
+		MaxIndex:        a.maxI,
+	}
+	if !a.seen {
+		j.MinIndex, j.MaxIndex = -1, -1
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Accumulator) UnmarshalJSON(data []byte) error {
+	var j accumulatorJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*a = Accumulator{
+		count: j.Count,
+		sum:   kahan{j.Sum, j.SumCompensation},
+		mean:  j.Mean,
+		m2:    j.M2,
+		min:   j.Min,
+		minI:  j.MinIndex,
+		max:   j.Max,
+		maxI:  j.MaxIndex,
+		seen:  j.MinIndex >= 0 && j.MaxIndex >= 0,
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package number
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestAccumulatorSumInf(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		samples []float64
+		want    float64
+	}{
+		{[]float64{inf, 1}, inf},
+		{[]float64{1, inf, 1}, inf},
+		{[]float64{-inf, 1, 2}, -inf},
+		{[]float64{math.MaxFloat64, math.MaxFloat64, 1}, inf},
+		{[]float64{inf, -inf}, math.NaN()},
+		{[]float64{math.NaN(), 1}, math.NaN()},
+	}
+
+	for _, tt := range tests {
+		var a, b Accumulator
+		for i, x := range tt.samples {
+			a.Add(x)
+			if i%2 == 0 {
+				b.Add(x)
+			} else {
+				var o Accumulator
+				o.Add(x)
+				b.Merge(&o)
+			}
+		}
+		for _, got := range []float64{a.Sum(), b.Sum()} {
+			if got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+				t.Errorf("Sum of %v = %v, want %v", tt.samples, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestAccumulatorJSON(t *testing.T) {
+	var a Accumulator
+	for _, x := range []float64{3, 1, 4, 1, 5} {
+		a.Add(x)
+	}
+
+	// a value, not a pointer, must be encoded with its state as well
+	for _, v := range []interface{}{a, &a, []Accumulator{a}} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []Accumulator
+		if data[0] == '[' {
+			err = json.Unmarshal(data, &got)
+		} else {
+			got = make([]Accumulator, 1)
+			err = json.Unmarshal(data, &got[0])
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0] != a {
+			t.Errorf("json round trip of %T = %+v, want %+v", v, got[0], a)
+		}
+	}
+}
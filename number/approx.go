@@ -0,0 +1,122 @@
+package number
+
+import (
+	"math"
+)
+
+// AlmostEqual reports whether a and b are equal within an absolute tolerance
+// absTol or a relative tolerance relTol, i.e.
+//     |a-b| <= max(relTol*max(|a|, |b|), absTol)
+// like Python's math.isclose. relTol alone does not work for values close to
+// 0, so absTol should be set as well when comparing to 0. NaN is not equal to
+// anything, while infinities are equal to themselves only.
+func AlmostEqual(a, b, absTol, relTol float64) bool {
+	if a == b {
+		return true
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+
+	d := math.Abs(a - b)
+	return d <= relTol*math.Max(math.Abs(a), math.Abs(b)) || d <= absTol
+}
+
+// orderedBits maps a float64 to a uint64 that has the same order, such that
+// adjacent floats are mapped to adjacent integers, and both zeros to the same
+// integer.
+func orderedBits(f float64) uint64 {
+	b := math.Float64bits(f)
+	if b>>63 == 1 {
+		// negative, reverse the order below 1<<63
+		return 1<<63 - (b & (1<<63 - 1))
+	}
+	return b | 1<<63
+}
+
+// ULPDistance returns the number of representable float64 values between a and
+// b, i.e. how many units in the last place they are apart. +0 and -0 are 0 ULP
+// apart, and the largest finite float64 is 1 ULP from +Inf. If either of a and
+// b is NaN, the result is math.MaxUint64.
+func ULPDistance(a, b float64) uint64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.MaxUint64
+	}
+
+	x, y := orderedBits(a), orderedBits(b)
+	if x > y {
+		return x - y
+	}
+	return y - x
+}
+
+// AlmostEqualULP reports whether a and b are at most maxULP units in the last
+// place apart. Unlike relative tolerance, it scales with the magnitude of the
+// values automatically, but it is meaningless around 0, where adjacent floats
+// are extremely close.
+func AlmostEqualULP(a, b float64, maxULP uint64) bool {
+	return ULPDistance(a, b) <= maxULP
+}
+
+// NextUp returns the smallest float64 greater than x. NextUp(+Inf) is +Inf and
+// NextUp(NaN) is NaN.
+func NextUp(x float64) float64 {
+	return math.Nextafter(x, math.Inf(1))
+}
+
+// NextDown returns the largest float64 less than x. NextDown(-Inf) is -Inf and
+// NextDown(NaN) is NaN.
+func NextDown(x float64) float64 {
+	return math.Nextafter(x, math.Inf(-1))
+}
+
+// AddULP returns the float64 that is n units in the last place away from x,
+// upward if n is positive and downward if it is negative. The result saturates
+// at ±Inf. AddULP(NaN, n) is NaN.
+func AddULP(x float64, n int64) float64 {
+	if math.IsNaN(x) || n == 0 {
+		return x
+	}
+
+	// work on the ordered integer representation, so that stepping across 0
+	// and exponent boundaries is correct
+	u := orderedBits(x)
+	lo, hi := orderedBits(math.Inf(-1)), orderedBits(math.Inf(1))
+	if n > 0 {
+		if uint64(n) >= hi-u {
+			return math.Inf(1)
+		}
+		u += uint64(n)
+	} else {
+		// -n may overflow, so negate n+1 instead
+		m := uint64(-(n + 1)) + 1
+		if m >= u-lo {
+			return math.Inf(-1)
+		}
+		u -= m
+	}
+
+	if u >= 1<<63 {
+		return math.Float64frombits(u &^ (1 << 63))
+	}
+	return math.Float64frombits(1<<63 | (1<<63 - u))
+}
+
+// AlmostEqualSlices compares a and b element-wise with AlmostEqual. If they
+// are not all almost equal, it returns the index of the first mismatch and
+// |a[i]-b[i]| there, with ok == false. If the lengths differ and the common
+// prefix matches, the index is the length of the shorter one, and the
+// difference is NaN.
+func AlmostEqualSlices(a, b []float64, absTol, relTol float64) (i int, diff float64, ok bool) {
+	n := Min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if !AlmostEqual(a[i], b[i], absTol, relTol) {
+			return i, math.Abs(a[i] - b[i]), false
+		}
+	}
+	if len(a) != len(b) {
+		return n, math.NaN(), false
+	}
+
+	return -1, 0, true
+}
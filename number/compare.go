@@ -1,15 +1,22 @@
 package number
 
-// drunk...
-
-func Max(a, b int) int {
+// Max returns the larger one of a and b.
+//
+// For floats, if either a or b is NaN, the result depends on the order of the
+// arguments, since NaN compares false with everything.
+func Max[T Ordered](a, b T) T {
 	if a > b {
 		return a
 	}
 	return b
 }
 
-func MaxN(a []int) (int, int) {
+// MaxN returns the index and the value of the largest element in a. If there
+// are multiple largest elements, the first one wins.
+//
+// MaxN panics if a is empty. See MaxNChecked for a non-panicking version and
+// MaxNFloat for explicit NaN handling.
+func MaxN[T Ordered](a []T) (int, T) {
 	maxI := 0
 	for i := 1; i < len(a); i++ {
 		if a[i] > a[maxI] {
@@ -20,326 +27,416 @@ func MaxN(a []int) (int, int) {
 	return maxI, a[maxI]
 }
 
-func MaxInt8(a, b int8) int8 {
-	if a > b {
+// Min returns the smaller one of a and b.
+//
+// For floats, if either a or b is NaN, the result depends on the order of the
+// arguments, since NaN compares false with everything.
+func Min[T Ordered](a, b T) T {
+	if a < b {
 		return a
 	}
 	return b
 }
 
-func MaxInt8N(a []int8) (int, int8) {
-	maxI := 0
+// MinN returns the index and the value of the smallest element in a. If there
+// are multiple smallest elements, the first one wins.
+//
+// MinN panics if a is empty. See MinNChecked for a non-panicking version and
+// MinNFloat for explicit NaN handling.
+func MinN[T Ordered](a []T) (int, T) {
+	minI := 0
 	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
+		if a[i] < a[minI] {
+			minI = i
 		}
 	}
 
-	return maxI, a[maxI]
+	return minI, a[minI]
 }
 
-func MaxInt16(a, b int16) int16 {
-	if a > b {
-		return a
+// MaxNChecked is like MaxN, but returns ok == false instead of panicking when a
+// is empty.
+func MaxNChecked[T Ordered](a []T) (i int, v T, ok bool) {
+	if len(a) == 0 {
+		return -1, v, false
+	}
+	i, v = MaxN(a)
+	return i, v, true
+}
+
+// MinNChecked is like MinN, but returns ok == false instead of panicking when a
+// is empty.
+func MinNChecked[T Ordered](a []T) (i int, v T, ok bool) {
+	if len(a) == 0 {
+		return -1, v, false
+	}
+	i, v = MinN(a)
+	return i, v, true
+}
+
+// NaNPolicy specifies how NaN elements are treated by MaxNFloat and MinNFloat.
+type NaNPolicy int
+
+const (
+	// NaNPropagate makes the first NaN in the slice the result.
+	NaNPropagate NaNPolicy = iota
+	// NaNIgnore skips NaN elements. If all elements are NaN, there is no
+	// result.
+	NaNIgnore
+	// NaNSmallest treats NaN as smaller than every other value, including
+	// -Inf.
+	NaNSmallest
+)
+
+// MaxNFloat returns the index and the value of the largest element in a,
+// treating NaN according to policy. If there are multiple largest elements,
+// the first one wins. ok is false if a is empty, or if every element is NaN
+// and policy is NaNIgnore.
+func MaxNFloat[T Float](a []T, policy NaNPolicy) (i int, v T, ok bool) {
+	return extremeFloat(a, policy, false)
+}
+
+// MinNFloat returns the index and the value of the smallest element in a,
+// treating NaN according to policy. If there are multiple smallest elements,
+// the first one wins. ok is false if a is empty, or if every element is NaN
+// and policy is NaNIgnore.
+func MinNFloat[T Float](a []T, policy NaNPolicy) (i int, v T, ok bool) {
+	return extremeFloat(a, policy, true)
+}
+
+func extremeFloat[T Float](a []T, policy NaNPolicy, min bool) (int, T, bool) {
+	best := -1
+	for i, x := range a {
+		if x != x {
+			switch policy {
+			case NaNPropagate:
+				return i, x, true
+			case NaNSmallest:
+				if min {
+					return i, x, true
+				}
+				if best == -1 {
+					best = i
+				}
+			}
+			continue
+		}
+
+		if best == -1 || a[best] != a[best] ||
+			(min && x < a[best]) || (!min && x > a[best]) {
+			best = i
+		}
 	}
-	return b
+
+	if best == -1 {
+		var zero T
+		return -1, zero, false
+	}
+
+	return best, a[best], true
 }
 
-func MaxInt16N(a []int16) (int, int16) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
+// ArgMax returns the indices of all elements in a that are equal to the
+// largest one, in ascending order. NaN elements are ignored. ArgMax returns
+// nil if a is empty or contains only NaN.
+func ArgMax[T Ordered](a []T) []int {
+	return argExtreme(a, false)
+}
+
+// ArgMin returns the indices of all elements in a that are equal to the
+// smallest one, in ascending order. NaN elements are ignored. ArgMin returns
+// nil if a is empty or contains only NaN.
+func ArgMin[T Ordered](a []T) []int {
+	return argExtreme(a, true)
+}
+
+func argExtreme[T Ordered](a []T, min bool) []int {
+	var idx []int
+	for i, x := range a {
+		// only NaN is not equal to itself
+		if x != x {
+			continue
+		}
+
+		if len(idx) == 0 {
+			idx = append(idx, i)
+			continue
+		}
+
+		best := a[idx[0]]
+		switch {
+		case x == best:
+			idx = append(idx, i)
+		case (min && x < best) || (!min && x > best):
+			idx = append(idx[:0], i)
 		}
 	}
 
-	return maxI, a[maxI]
+	return idx
 }
 
-func MaxInt32(a, b int32) int32 {
-	if a > b {
-		return a
+// Clamp returns v limited to the range [lo, hi]. It panics if lo > hi.
+//
+// For floats, Clamp(NaN, lo, hi) is NaN.
+func Clamp[T Ordered](v, lo, hi T) T {
+	if lo > hi {
+		panic("number: Clamp with lo > hi")
 	}
-	return b
+
+	switch {
+	case v < lo:
+		return lo
+	case v > hi:
+		return hi
+	}
+	return v
 }
 
-func MaxInt32N(a []int32) (int, int32) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
+// Abs returns the absolute value of x.
+//
+// For a signed integer T, the absolute value of the smallest value of T is not
+// representable, so Abs(math.MinInt64) is math.MinInt64 itself, which is
+// still negative, like in two's complement negation. For floats, Abs(-0) is +0
+// and Abs(NaN) is NaN.
+func Abs[T Real](x T) T {
+	if x <= 0 {
+		// 0 - x rather than -x, so that -0 becomes +0
+		return 0 - x
 	}
+	return x
+}
 
-	return maxI, a[maxI]
+// Sign returns -1 if x < 0, +1 if x > 0, and 0 if x is 0 (including -0) or
+// NaN.
+func Sign[T Real](x T) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+// The per-type functions below predate Max, MaxN, Min and MinN and are kept
+// for compatibility.
+
+// MaxInt8 is Max for int8.
+func MaxInt8(a, b int8) int8 {
+	return Max(a, b)
+}
+
+// MaxInt8N is MaxN for int8.
+func MaxInt8N(a []int8) (int, int8) {
+	return MaxN(a)
+}
+
+// MaxInt16 is Max for int16.
+func MaxInt16(a, b int16) int16 {
+	return Max(a, b)
+}
+
+// MaxInt16N is MaxN for int16.
+func MaxInt16N(a []int16) (int, int16) {
+	return MaxN(a)
 }
 
+// MaxInt32 is Max for int32.
+func MaxInt32(a, b int32) int32 {
+	return Max(a, b)
+}
+
+// MaxInt32N is MaxN for int32.
+func MaxInt32N(a []int32) (int, int32) {
+	return MaxN(a)
+}
+
+// MaxInt64 is Max for int64.
 func MaxInt64(a, b int64) int64 {
-	if a > b {
-		return a
-	}
-	return b
+	return Max(a, b)
 }
 
+// MaxInt64N is MaxN for int64.
 func MaxInt64N(a []int64) (int, int64) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
-	}
-
-	return maxI, a[maxI]
+	return MaxN(a)
 }
 
+// MaxUint is Max for uint.
 func MaxUint(a, b uint) uint {
-	if a > b {
-		return a
-	}
-	return b
+	return Max(a, b)
 }
 
+// MaxUintN is MaxN for uint.
 func MaxUintN(a []uint) (int, uint) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
-	}
-
-	return maxI, a[maxI]
+	return MaxN(a)
 }
 
+// MaxUint8 is Max for uint8.
 func MaxUint8(a, b uint8) uint8 {
-	if a > b {
-		return a
-	}
-	return b
+	return Max(a, b)
 }
 
+// MaxUint8N is MaxN for uint8.
 func MaxUint8N(a []uint8) (int, uint8) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
-	}
-
-	return maxI, a[maxI]
+	return MaxN(a)
 }
 
+// MaxUint16 is Max for uint16.
 func MaxUint16(a, b uint16) uint16 {
-	if a > b {
-		return a
-	}
-	return b
+	return Max(a, b)
 }
 
+// MaxUint16N is MaxN for uint16.
 func MaxUint16N(a []uint16) (int, uint16) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
-	}
-
-	return maxI, a[maxI]
+	return MaxN(a)
 }
 
+// MaxUint32 is Max for uint32.
 func MaxUint32(a, b uint32) uint32 {
-	if a > b {
-		return a
-	}
-	return b
+	return Max(a, b)
 }
 
+// MaxUint32N is MaxN for uint32.
 func MaxUint32N(a []uint32) (int, uint32) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
-	}
-
-	return maxI, a[maxI]
+	return MaxN(a)
 }
 
+// MaxUint64 is Max for uint64.
 func MaxUint64(a, b uint64) uint64 {
-	if a > b {
-		return a
-	}
-	return b
+	return Max(a, b)
 }
 
+// MaxUint64N is MaxN for uint64.
 func MaxUint64N(a []uint64) (int, uint64) {
-	maxI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] > a[maxI] {
-			maxI = i
-		}
-	}
+	return MaxN(a)
+}
 
-	return maxI, a[maxI]
+// MaxFloat32 is Max for float32.
+func MaxFloat32(a, b float32) float32 {
+	return Max(a, b)
 }
 
-func Min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+// MaxFloat32N is MaxN for float32.
+func MaxFloat32N(a []float32) (int, float32) {
+	return MaxN(a)
 }
 
-func MinN(a []int) (int, int) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
+// MaxFloat64 is Max for float64.
+func MaxFloat64(a, b float64) float64 {
+	return Max(a, b)
+}
 
-	return minI, a[minI]
+// MaxFloat64N is MaxN for float64.
+func MaxFloat64N(a []float64) (int, float64) {
+	return MaxN(a)
 }
 
+// MinInt8 is Min for int8.
 func MinInt8(a, b int8) int8 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinInt8N is MinN for int8.
 func MinInt8N(a []int8) (int, int8) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
+	return MinN(a)
+}
 
-	return minI, a[minI]
+// MinInt16 is Min for int16.
+func MinInt16(a, b int16) int16 {
+	return Min(a, b)
 }
 
+// MinInt16N is MinN for int16.
+func MinInt16N(a []int16) (int, int16) {
+	return MinN(a)
+}
+
+// MinInt32 is Min for int32.
 func MinInt32(a, b int32) int32 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinInt32N is MinN for int32.
 func MinInt32N(a []int32) (int, int32) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
-
-	return minI, a[minI]
+	return MinN(a)
 }
 
+// MinInt64 is Min for int64.
 func MinInt64(a, b int64) int64 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinInt64N is MinN for int64.
 func MinInt64N(a []int64) (int, int64) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
-
-	return minI, a[minI]
+	return MinN(a)
 }
 
+// MinUint is Min for uint.
 func MinUint(a, b uint) uint {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinUintN is MinN for uint.
 func MinUintN(a []uint) (int, uint) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
-
-	return minI, a[minI]
+	return MinN(a)
 }
 
+// MinUint8 is Min for uint8.
 func MinUint8(a, b uint8) uint8 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinUint8N is MinN for uint8.
 func MinUint8N(a []uint8) (int, uint8) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
-
-	return minI, a[minI]
+	return MinN(a)
 }
 
+// MinUint16 is Min for uint16.
 func MinUint16(a, b uint16) uint16 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinUint16N is MinN for uint16.
 func MinUint16N(a []uint16) (int, uint16) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
-
-	return minI, a[minI]
+	return MinN(a)
 }
 
+// MinUint32 is Min for uint32.
 func MinUint32(a, b uint32) uint32 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinUint32N is MinN for uint32.
 func MinUint32N(a []uint32) (int, uint32) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
-
-	return minI, a[minI]
+	return MinN(a)
 }
 
+// MinUint64 is Min for uint64.
 func MinUint64(a, b uint64) uint64 {
-	if a < b {
-		return a
-	}
-	return b
+	return Min(a, b)
 }
 
+// MinUint64N is MinN for uint64.
 func MinUint64N(a []uint64) (int, uint64) {
-	minI := 0
-	for i := 1; i < len(a); i++ {
-		if a[i] < a[minI] {
-			minI = i
-		}
-	}
+	return MinN(a)
+}
 
-	return minI, a[minI]
+// MinFloat32 is Min for float32.
+func MinFloat32(a, b float32) float32 {
+	return Min(a, b)
+}
+
+// MinFloat32N is MinN for float32.
+func MinFloat32N(a []float32) (int, float32) {
+	return MinN(a)
+}
+
+// MinFloat64 is Min for float64.
+func MinFloat64(a, b float64) float64 {
+	return Min(a, b)
+}
+
+// MinFloat64N is MinN for float64.
+func MinFloat64N(a []float64) (int, float64) {
+	return MinN(a)
 }
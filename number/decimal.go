@@ -0,0 +1,489 @@
+package number
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDivisionByZero = errors.New("division by zero")
+	ErrInexact        = errors.New("result cannot be represented exactly")
+)
+
+// Decimal is an arbitrary-precision decimal number, represented as
+// coefficient * 10^exponent. The zero value is 0.
+//
+// A Decimal is immutable once created; all the operations return a new
+// Decimal and never modify their operands, so it is safe to copy a Decimal
+// and to use it concurrently.
+//
+// Trailing zeros are significant in the representation: "1.50" is parsed into
+// 150 * 10^-2 and printed back as "1.50", while it compares equal to "1.5".
+type Decimal struct {
+	coef *big.Int // nil means 0
+	exp  int
+}
+
+// DecimalContext specifies the precision and the rounding mode used by its
+// arithmetic methods.
+type DecimalContext struct {
+	// Precision is the maximum number of significant digits of a result. 0
+	// means unlimited, in which case Add, Sub and Mul are exact, and Quo fails
+	// with ErrInexact if the quotient does not terminate.
+	Precision int
+	// Mode is the rounding mode applied when a result has more than Precision
+	// significant digits.
+	Mode RoundMode
+}
+
+// Decimal128 is the context of IEEE 754 decimal128: 34 significant digits with
+// banker's rounding.
+var Decimal128 = DecimalContext{Precision: 34, Mode: RoundHalfEven}
+
+// NewDecimal returns coef * 10^exp.
+func NewDecimal(coef int64, exp int) Decimal {
+	return Decimal{big.NewInt(coef), exp}
+}
+
+// NewDecimalFromBigInt returns coef * 10^exp. coef is copied.
+func NewDecimalFromBigInt(coef *big.Int, exp int) Decimal {
+	return Decimal{new(big.Int).Set(coef), exp}
+}
+
+// NewDecimalFromFloat returns the shortest decimal representation of v, i.e.
+// the one printed by strconv.FormatFloat(v, 'g', -1, 64). It fails if v is NaN
+// or ±Inf.
+func NewDecimalFromFloat(v float64) (Decimal, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Decimal{}, fmt.Errorf("cannot convert %v to Decimal", v)
+	}
+
+	return ParseDecimal(strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// maxParseExponent is the largest magnitude of the exponent accepted by
+// ParseDecimal. Without it, a short input such as "1e999999999" would make
+// String, or the alignment of its operands by Add and Cmp, allocate gigabytes.
+const maxParseExponent = 100000
+
+// ParseDecimal parses s in the form of [+-]digits[.digits][(e|E)[+-]digits],
+// e.g. "-12.30" or "1.5e-3". The exponent after e or E must be within
+// ±100000. The returned error, if any, wraps either strconv.ErrSyntax or
+// strconv.ErrRange.
+func ParseDecimal(s string) (Decimal, error) {
+	syntaxErr := fmt.Errorf("parsing decimal %q: %w", s, strconv.ErrSyntax)
+	rangeErr := fmt.Errorf("parsing decimal %q: %w", s, strconv.ErrRange)
+
+	mantissa, exp := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa = s[:i]
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return Decimal{}, rangeErr
+			}
+			return Decimal{}, syntaxErr
+		}
+		if e > maxParseExponent || e < -maxParseExponent {
+			return Decimal{}, rangeErr
+		}
+		exp = int(e)
+	}
+
+	neg := false
+	if mantissa != "" && (mantissa[0] == '+' || mantissa[0] == '-') {
+		neg = mantissa[0] == '-'
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+	digits := intPart + fracPart
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, syntaxErr
+	}
+
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+	exp -= len(fracPart)
+	if exp < math.MinInt32 || exp > math.MaxInt32 {
+		return Decimal{}, rangeErr
+	}
+
+	return Decimal{coef, exp}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s cannot be parsed. It
+// is intended for use in variable initializations.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Coefficient returns a copy of the coefficient of d.
+func (d Decimal) Coefficient() *big.Int {
+	return new(big.Int).Set(d.coefficient())
+}
+
+// Exponent returns the exponent of d.
+func (d Decimal) Exponent() int {
+	return d.exp
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+	return d.coef.Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{new(big.Int).Neg(d.coefficient()), d.exp}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{new(big.Int).Abs(d.coefficient()), d.exp}
+}
+
+// Cmp compares d and e, and returns -1 if d < e, 0 if d == e and +1 if d > e.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b, _ := align(d, e)
+	return a.Cmp(b)
+}
+
+// Add returns the exact d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b, exp := align(d, e)
+	return Decimal{a.Add(a, b), exp}
+}
+
+// Sub returns the exact d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	a, b, exp := align(d, e)
+	return Decimal{a.Sub(a, b), exp}
+}
+
+// Mul returns the exact d * e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{new(big.Int).Mul(d.coefficient(), e.coefficient()), d.exp + e.exp}
+}
+
+// Round returns d rounded to places decimal places using mode. The result has
+// exactly places digits after the decimal point, padding with zeros if
+// necessary, so NewDecimal(123, -1).Round(2, RoundHalfEven) prints "12.30". A
+// negative places rounds to the left of the decimal point.
+func (d Decimal) Round(places int, mode RoundMode) Decimal {
+	return d.rescale(-places, mode)
+}
+
+// rescale returns d with the exponent set to exp, rounding with mode if
+// necessary.
+func (d Decimal) rescale(exp int, mode RoundMode) Decimal {
+	c := d.coefficient()
+	switch {
+	case exp > d.exp:
+		return Decimal{roundQuo(c, pow10(exp-d.exp), mode), exp}
+	case exp < d.exp:
+		return Decimal{new(big.Int).Mul(c, pow10(d.exp-exp)), exp}
+	}
+	return d
+}
+
+// reduce returns d with the trailing zeros of the coefficient removed.
+func (d Decimal) reduce() Decimal {
+	c := d.coefficient()
+	if c.Sign() == 0 {
+		return Decimal{}
+	}
+
+	c = new(big.Int).Set(c)
+	ten, r := big.NewInt(10), new(big.Int)
+	exp := d.exp
+	for {
+		q, _ := new(big.Int).QuoRem(c, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		c = q
+		exp++
+	}
+
+	return Decimal{c, exp}
+}
+
+// Rat returns d as a *big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat).SetInt(d.coefficient())
+	s := new(big.Rat).SetInt(pow10(Abs(d.exp)))
+	if d.exp < 0 {
+		return r.Quo(r, s)
+	}
+	return r.Mul(r, s)
+}
+
+// Float64 returns the nearest float64 of d, and whether it is exact.
+func (d Decimal) Float64() (float64, bool) {
+	return d.Rat().Float64()
+}
+
+// String formats d in plain notation without an exponent, e.g. "-12.30" or
+// "1500".
+func (d Decimal) String() string {
+	c := d.coefficient()
+	digits := new(big.Int).Abs(c).Text(10)
+
+	var b strings.Builder
+	if c.Sign() < 0 {
+		b.WriteByte('-')
+	}
+
+	switch {
+	case d.exp >= 0:
+		b.WriteString(digits)
+		if c.Sign() != 0 {
+			b.WriteString(strings.Repeat("0", d.exp))
+		}
+	case len(digits) > -d.exp:
+		b.WriteString(digits[:len(digits)+d.exp])
+		b.WriteByte('.')
+		b.WriteString(digits[len(digits)+d.exp:])
+	default:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -d.exp-len(digits)))
+		b.WriteString(digits)
+	}
+
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. d is encoded as a JSON number, not a
+// string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both a JSON number and
+// a JSON string. null is a no-op.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	return d.UnmarshalText(data)
+}
+
+// Value implements driver.Valuer. d is stored as its string form.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	case float64:
+		x, err := NewDecimalFromFloat(v)
+		if err != nil {
+			return err
+		}
+		*d = x
+		return nil
+	}
+
+	return fmt.Errorf("cannot scan %T into Decimal", src)
+}
+
+// Round returns d rounded to c.Precision significant digits using c.Mode.
+func (c DecimalContext) Round(d Decimal) Decimal {
+	if c.Precision <= 0 {
+		return d
+	}
+
+	n := numDigits(d.coefficient())
+	if n <= c.Precision {
+		return d
+	}
+
+	r := d.rescale(d.exp+n-c.Precision, c.Mode)
+	if numDigits(r.coef) > c.Precision {
+		// carried into a new digit, e.g. 999 -> 1000, the dropped digit is 0
+		r.coef.Quo(r.coef, big.NewInt(10))
+		r.exp++
+	}
+
+	return r
+}
+
+// Add returns d + e rounded by c.
+func (c DecimalContext) Add(d, e Decimal) Decimal {
+	return c.Round(d.Add(e))
+}
+
+// Sub returns d - e rounded by c.
+func (c DecimalContext) Sub(d, e Decimal) Decimal {
+	return c.Round(d.Sub(e))
+}
+
+// Mul returns d * e rounded by c.
+func (c DecimalContext) Mul(d, e Decimal) Decimal {
+	return c.Round(d.Mul(e))
+}
+
+// Quo returns d / e rounded by c. It fails with ErrDivisionByZero if e is 0,
+// and with ErrInexact if c.Precision is 0 and the quotient does not terminate.
+func (c DecimalContext) Quo(d, e Decimal) (Decimal, error) {
+	if e.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	n := new(big.Int).Set(d.coefficient())
+	m := new(big.Int).Set(e.coefficient())
+	if m.Sign() < 0 {
+		n.Neg(n)
+		m.Neg(m)
+	}
+
+	if n.Sign() == 0 {
+		return Decimal{n, d.exp - e.exp}, nil
+	}
+	if c.Precision <= 0 {
+		return quoExact(n, m, d.exp-e.exp)
+	}
+
+	// scale n so that the integer quotient has exactly c.Precision digits,
+	// then round only once with the exact remainder. With this shift, the
+	// quotient has either c.Precision or c.Precision+1 digits.
+	shift := c.Precision + numDigits(m) - numDigits(n)
+	num, den := n, m
+	if shift >= 0 {
+		num = new(big.Int).Mul(n, pow10(shift))
+	} else {
+		den = new(big.Int).Mul(m, pow10(-shift))
+	}
+	if numDigits(new(big.Int).Quo(num, den)) > c.Precision {
+		shift--
+		if shift >= 0 {
+			num.Quo(num, big.NewInt(10))
+		} else {
+			den = new(big.Int).Mul(m, pow10(-shift))
+		}
+	}
+
+	// the rounding may still carry into one more digit, e.g. 9.99 to 10.0,
+	// which c.Round drops exactly
+	coef := roundQuo(num, den, c.Mode)
+	return c.Round(Decimal{coef, d.exp - e.exp - shift}), nil
+}
+
+// quoExact returns n / m * 10^exp if it terminates. m must be positive.
+func quoExact(n, m *big.Int, exp int) (Decimal, error) {
+	g := new(big.Int).GCD(nil, nil, new(big.Int).Abs(n), m)
+	n.Quo(n, g)
+	m.Quo(m, g)
+
+	// the quotient terminates iff m = 2^a * 5^b
+	var twos, fives int
+	r := new(big.Int)
+	for two := big.NewInt(2); m.Bit(0) == 0; twos++ {
+		m.Quo(m, two)
+	}
+	for five := big.NewInt(5); ; fives++ {
+		if q, _ := new(big.Int).QuoRem(m, five, r); r.Sign() == 0 {
+			m.Set(q)
+			continue
+		}
+		break
+	}
+	if m.Cmp(big.NewInt(1)) != 0 {
+		return Decimal{}, ErrInexact
+	}
+
+	// n / (2^a * 5^b) = n * 2^(k-a) * 5^(k-b) / 10^k
+	k := twos
+	if fives > k {
+		k = fives
+	}
+	n.Lsh(n, uint(k-twos))
+	n.Mul(n, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(k-fives)), nil))
+
+	return Decimal{n, exp - k}, nil
+}
+
+// align returns the coefficients of d and e scaled to their common exponent,
+// which is also returned. The returned coefficients are always fresh copies.
+func align(d, e Decimal) (*big.Int, *big.Int, int) {
+	a := new(big.Int).Set(d.coefficient())
+	b := new(big.Int).Set(e.coefficient())
+	switch {
+	case d.exp > e.exp:
+		a.Mul(a, pow10(d.exp-e.exp))
+		return a, b, e.exp
+	case d.exp < e.exp:
+		b.Mul(b, pow10(e.exp-d.exp))
+	}
+	return a, b, d.exp
+}
+
+// numDigits returns the number of decimal digits of |n|. 0 has 1 digit.
+func numDigits(n *big.Int) int {
+	if n.Sign() == 0 {
+		return 1
+	}
+
+	// BitLen is an estimate of log2, refine it with a comparison
+	d := int(float64(n.BitLen()-1)*math.Log10(2)) + 1
+	if new(big.Int).Abs(n).Cmp(pow10(d)) >= 0 {
+		d++
+	}
+	return d
+}
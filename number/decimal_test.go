@@ -0,0 +1,143 @@
+package number
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestDecimalContextQuo(t *testing.T) {
+	tests := []struct {
+		d, e string
+		prec int
+		mode RoundMode
+		want string
+	}{
+		{"29", "2", 1, RoundHalfUp, "10"},
+		{"29", "2", 2, RoundHalfUp, "15"},
+		{"98695", "42", 2, RoundHalfEven, "2300"},
+		{"98695", "42", 4, RoundHalfEven, "2350"},
+		{"1", "3", 3, RoundHalfEven, "0.333"},
+		{"2", "3", 3, RoundHalfEven, "0.667"},
+		{"2", "3", 3, RoundTowardZero, "0.666"},
+		{"-1", "3", 2, RoundFloor, "-0.34"},
+		{"-1", "3", 2, RoundCeiling, "-0.33"},
+		{"1", "-8", 2, RoundHalfEven, "-0.12"},
+
+		// ties
+		{"25", "2", 2, RoundHalfEven, "12"},
+		{"27", "2", 2, RoundHalfEven, "14"},
+		{"25", "2", 2, RoundHalfUp, "13"},
+		{"25", "2", 2, RoundHalfDown, "12"},
+		{"-25", "2", 2, RoundHalfUp, "-13"},
+		{"-25", "2", 2, RoundHalfEven, "-12"},
+		{"25", "-2", 2, RoundHalfDown, "-12"},
+		{"1", "8", 2, RoundHalfEven, "0.12"},
+		{"3", "8", 2, RoundHalfEven, "0.38"},
+		{"3", "8", 2, RoundHalfUp, "0.38"},
+		{"3", "8", 2, RoundHalfDown, "0.37"},
+		{"2.5", "0.01", 2, RoundHalfEven, "250"},
+		{"2.25e-7", "1e3", 2, RoundHalfEven, "0.00000000022"},
+
+		// carries into one more digit
+		{"19999", "2", 4, RoundHalfUp, "10000"},
+		{"19999", "2", 4, RoundHalfDown, "9999"},
+		{"999", "1000", 2, RoundHalfEven, "1.0"},
+
+		// exact
+		{"1", "4", 4, RoundHalfEven, "0.2500"},
+		{"0", "7", 3, RoundHalfEven, "0"},
+		{"1.50", "1", 3, RoundHalfEven, "1.50"},
+		{"1.50", "1", 2, RoundHalfEven, "1.5"},
+	}
+
+	for _, tt := range tests {
+		c := DecimalContext{Precision: tt.prec, Mode: tt.mode}
+		got, err := c.Quo(MustParseDecimal(tt.d), MustParseDecimal(tt.e))
+		if err != nil {
+			t.Errorf("Quo(%s, %s) with %d %v: %v", tt.d, tt.e, tt.prec, tt.mode, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Quo(%s, %s) with %d %v = %s, want %s", tt.d, tt.e, tt.prec, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+		err  error
+	}{
+		{"-12.30", "-12.30", nil},
+		{"1.5e-3", "0.0015", nil},
+		{"+2E2", "200", nil},
+		{"1e100000", "", nil},
+		{"1e-100000", "", nil},
+		{"1e100001", "", strconv.ErrRange},
+		{"1e-100001", "", strconv.ErrRange},
+		{"1e999999999", "", strconv.ErrRange},
+		{"1e99999999999", "", strconv.ErrRange},
+		{"", "", strconv.ErrSyntax},
+		{"1.2.3", "", strconv.ErrSyntax},
+		{"1e", "", strconv.ErrSyntax},
+		{"e5", "", strconv.ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.s)
+		switch {
+		case tt.err != nil:
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseDecimal(%q) error = %v, want %v", tt.s, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("ParseDecimal(%q): %v", tt.s, err)
+		case tt.want != "" && d.String() != tt.want:
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.s, d, tt.want)
+		}
+	}
+
+	var d Decimal
+	if err := json.Unmarshal([]byte(`"1e999999999"`), &d); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("json.Unmarshal(1e999999999) error = %v, want %v", err, strconv.ErrRange)
+	}
+}
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want string // empty for an error
+	}{
+		{`1.5`, "1.5"},
+		{`"1.5"`, "1.5"},
+		{`-2e3`, "-2000"},
+		{`"1.5`, ""},
+		{`1.5"`, ""},
+		{`""1.5""`, ""},
+		{`"`, ""},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		var d Decimal
+		err := d.UnmarshalJSON([]byte(tt.data))
+		switch {
+		case tt.want == "":
+			if err == nil {
+				t.Errorf("UnmarshalJSON(%s) = %s, want an error", tt.data, d)
+			}
+		case err != nil:
+			t.Errorf("UnmarshalJSON(%s): %v", tt.data, err)
+		case d.String() != tt.want:
+			t.Errorf("UnmarshalJSON(%s) = %s, want %s", tt.data, d, tt.want)
+		}
+	}
+
+	d := NewDecimal(7, 0)
+	if err := d.UnmarshalJSON([]byte("null")); err != nil || d.String() != "7" {
+		t.Errorf("UnmarshalJSON(null) = %s, %v, want 7 unchanged", d, err)
+	}
+}
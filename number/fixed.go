@@ -0,0 +1,320 @@
+package number
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Q16 is a signed Q16.16 fixed-point number, i.e. an int32 whose lower 16 bits
+// are the fractional part. Its range is [-32768, 32768) with a resolution of
+// 2^-16.
+//
+// The arithmetic methods without a suffix wrap around on overflow, like Go's
+// integer arithmetic. The Checked variants report overflow instead, and the
+// Sat variants clamp the result to [MinQ16, MaxQ16]. Mul and Quo truncate
+// toward zero.
+type Q16 int32
+
+// Q32 is a signed Q32.32 fixed-point number, i.e. an int64 whose lower 32 bits
+// are the fractional part. Its range is [-2^31, 2^31) with a resolution of
+// 2^-32.
+//
+// Its methods follow the same conventions as Q16.
+type Q32 int64
+
+const (
+	Q16One Q16 = 1 << 16
+	MinQ16 Q16 = math.MinInt32
+	MaxQ16 Q16 = math.MaxInt32
+
+	Q32One Q32 = 1 << 32
+	MinQ32 Q32 = math.MinInt64
+	MaxQ32 Q32 = math.MaxInt64
+)
+
+// Q16FromInt returns i as a Q16.
+func Q16FromInt(i int16) Q16 {
+	return Q16(i) << 16
+}
+
+// Q16FromFloat returns v rounded to the nearest Q16 using mode. If v is out of
+// range, the result is saturated and ok is false. NaN results in (0, false).
+func Q16FromFloat(v float64, mode RoundMode) (q Q16, ok bool) {
+	if math.IsNaN(v) {
+		return 0, false
+	}
+
+	r := RoundWith(v*(1<<16), 0, mode)
+	switch {
+	case r > math.MaxInt32:
+		return MaxQ16, false
+	case r < math.MinInt32:
+		return MinQ16, false
+	}
+
+	return Q16(r), true
+}
+
+// narrowQ16 converts a Q16 computed in 64 bits back to Q16, saturating it if
+// it overflows.
+func narrowQ16(v int64) (Q16, bool) {
+	switch {
+	case v > math.MaxInt32:
+		return MaxQ16, false
+	case v < math.MinInt32:
+		return MinQ16, false
+	}
+
+	return Q16(v), true
+}
+
+// Float64 returns a as a float64. The conversion is exact.
+func (a Q16) Float64() float64 {
+	return float64(a) / (1 << 16)
+}
+
+// String returns the exact decimal representation of a, e.g. "-1.5".
+func (a Q16) String() string {
+	return fixedString(int64(a), 16)
+}
+
+// Add returns a + b.
+func (a Q16) Add(b Q16) Q16 {
+	return a + b
+}
+
+// AddChecked returns a + b. ok is false if the result overflows.
+func (a Q16) AddChecked(b Q16) (q Q16, ok bool) {
+	return narrowQ16(int64(a) + int64(b))
+}
+
+// AddSat returns a + b, saturated.
+func (a Q16) AddSat(b Q16) Q16 {
+	q, _ := a.AddChecked(b)
+	return q
+}
+
+// Sub returns a - b.
+func (a Q16) Sub(b Q16) Q16 {
+	return a - b
+}
+
+// SubChecked returns a - b. ok is false if the result overflows.
+func (a Q16) SubChecked(b Q16) (q Q16, ok bool) {
+	return narrowQ16(int64(a) - int64(b))
+}
+
+// SubSat returns a - b, saturated.
+func (a Q16) SubSat(b Q16) Q16 {
+	q, _ := a.SubChecked(b)
+	return q
+}
+
+// Mul returns a * b.
+func (a Q16) Mul(b Q16) Q16 {
+	return Q16(int64(a) * int64(b) / (1 << 16))
+}
+
+// MulChecked returns a * b. ok is false if the result overflows.
+func (a Q16) MulChecked(b Q16) (q Q16, ok bool) {
+	return narrowQ16(int64(a) * int64(b) / (1 << 16))
+}
+
+// MulSat returns a * b, saturated.
+func (a Q16) MulSat(b Q16) Q16 {
+	q, _ := a.MulChecked(b)
+	return q
+}
+
+// Quo returns a / b. It panics if b is 0.
+func (a Q16) Quo(b Q16) Q16 {
+	return Q16(int64(a) << 16 / int64(b))
+}
+
+// QuoChecked returns a / b. ok is false if b is 0 or the result overflows.
+func (a Q16) QuoChecked(b Q16) (q Q16, ok bool) {
+	if b == 0 {
+		return a.QuoSat(b), false
+	}
+	return narrowQ16(int64(a) << 16 / int64(b))
+}
+
+// QuoSat returns a / b, saturated. If b is 0, the result is MaxQ16, MinQ16 or
+// 0 depending on the sign of a.
+func (a Q16) QuoSat(b Q16) Q16 {
+	if b == 0 {
+		switch {
+		case a > 0:
+			return MaxQ16
+		case a < 0:
+			return MinQ16
+		}
+		return 0
+	}
+	q, _ := narrowQ16(int64(a) << 16 / int64(b))
+	return q
+}
+
+// Q32FromInt returns i as a Q32.
+func Q32FromInt(i int32) Q32 {
+	return Q32(i) << 32
+}
+
+// Q32FromFloat returns v rounded to the nearest Q32 using mode. If v is out of
+// range, the result is saturated and ok is false. NaN results in (0, false).
+func Q32FromFloat(v float64, mode RoundMode) (q Q32, ok bool) {
+	if math.IsNaN(v) {
+		return 0, false
+	}
+
+	r := RoundWith(v*(1<<32), 0, mode)
+	switch {
+	case r >= math.MaxInt64: // float64(math.MaxInt64) is 2^63
+		return MaxQ32, false
+	case r < math.MinInt64:
+		return MinQ32, false
+	}
+
+	return Q32(r), true
+}
+
+// toQ32 converts a magnitude and a sign to Q32. The returned value wraps
+// around on overflow, in which case ok is false.
+func toQ32(mag uint64, neg, overflow bool) (q Q32, ok bool) {
+	if neg {
+		return Q32(-int64(mag)), !overflow && mag <= 1<<63
+	}
+	return Q32(int64(mag)), !overflow && mag <= math.MaxInt64
+}
+
+// satQ32 is like toQ32 but saturates on overflow.
+func satQ32(mag uint64, neg, overflow bool) Q32 {
+	q, ok := toQ32(mag, neg, overflow)
+	switch {
+	case ok:
+		return q
+	case neg:
+		return MinQ32
+	}
+	return MaxQ32
+}
+
+func (a Q32) magnitude() (uint64, bool) {
+	if a < 0 {
+		return uint64(-a), true // also works for MinQ32
+	}
+	return uint64(a), false
+}
+
+// Float64 returns the nearest float64 of a.
+func (a Q32) Float64() float64 {
+	return float64(a) / (1 << 32)
+}
+
+// String returns the exact decimal representation of a, e.g. "-1.5".
+func (a Q32) String() string {
+	return fixedString(int64(a), 32)
+}
+
+// Add returns a + b.
+func (a Q32) Add(b Q32) Q32 {
+	return a + b
+}
+
+// AddChecked returns a + b. ok is false if the result overflows.
+func (a Q32) AddChecked(b Q32) (q Q32, ok bool) {
+	return AddChecked(a, b)
+}
+
+// AddSat returns a + b, saturated.
+func (a Q32) AddSat(b Q32) Q32 {
+	return AddSat(a, b)
+}
+
+// Sub returns a - b.
+func (a Q32) Sub(b Q32) Q32 {
+	return a - b
+}
+
+// SubChecked returns a - b. ok is false if the result overflows.
+func (a Q32) SubChecked(b Q32) (q Q32, ok bool) {
+	return SubChecked(a, b)
+}
+
+// SubSat returns a - b, saturated.
+func (a Q32) SubSat(b Q32) Q32 {
+	return SubSat(a, b)
+}
+
+func (a Q32) mul(b Q32) (mag uint64, neg, overflow bool) {
+	ma, na := a.magnitude()
+	mb, nb := b.magnitude()
+	hi, lo := bits.Mul64(ma, mb)
+	return hi<<32 | lo>>32, na != nb, hi>>32 != 0
+}
+
+// Mul returns a * b.
+func (a Q32) Mul(b Q32) Q32 {
+	q, _ := toQ32(a.mul(b))
+	return q
+}
+
+// MulChecked returns a * b. ok is false if the result overflows.
+func (a Q32) MulChecked(b Q32) (q Q32, ok bool) {
+	return toQ32(a.mul(b))
+}
+
+// MulSat returns a * b, saturated.
+func (a Q32) MulSat(b Q32) Q32 {
+	return satQ32(a.mul(b))
+}
+
+// quo panics if b is 0.
+func (a Q32) quo(b Q32) (mag uint64, neg, overflow bool) {
+	ma, na := a.magnitude()
+	mb, nb := b.magnitude()
+	// divide the 96-bit a<<32 in two steps, so that the quotient wraps around
+	// instead of panicking on overflow
+	hi, lo := ma>>32, ma<<32
+	qhi, r := hi/mb, hi%mb
+	qlo, _ := bits.Div64(r, lo, mb)
+	return qlo, na != nb, qhi != 0
+}
+
+// Quo returns a / b. It panics if b is 0.
+func (a Q32) Quo(b Q32) Q32 {
+	q, _ := toQ32(a.quo(b))
+	return q
+}
+
+// QuoChecked returns a / b. ok is false if b is 0 or the result overflows.
+func (a Q32) QuoChecked(b Q32) (q Q32, ok bool) {
+	if b == 0 {
+		return a.QuoSat(b), false
+	}
+	return toQ32(a.quo(b))
+}
+
+// QuoSat returns a / b, saturated. If b is 0, the result is MaxQ32, MinQ32 or
+// 0 depending on the sign of a.
+func (a Q32) QuoSat(b Q32) Q32 {
+	if b == 0 {
+		switch {
+		case a > 0:
+			return MaxQ32
+		case a < 0:
+			return MinQ32
+		}
+		return 0
+	}
+	return satQ32(a.quo(b))
+}
+
+// fixedString returns the exact decimal representation of raw / 2^fracBits.
+func fixedString(raw int64, fracBits int) string {
+	// raw / 2^n = raw * 5^n / 10^n
+	coef := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(fracBits)), nil)
+	coef.Mul(coef, big.NewInt(raw))
+	return Decimal{coef, -fracBits}.reduce().String()
+}
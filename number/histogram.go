@@ -0,0 +1,434 @@
+package number
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+var ErrHistogramMismatch = errors.New("histograms have different bucket layouts")
+
+// layout maps values to bucket indices. Implementations must be comparable, so
+// that Merge can check two histograms are compatible.
+type layout interface {
+	// index returns the index of the bucket of x, which may be out of
+	// [0, size()) if x is out of range.
+	index(x float64) int
+	// bounds returns the range [lo, hi) of the i-th bucket.
+	bounds(i int) (lo, hi float64)
+	size() int
+}
+
+type linearLayout struct {
+	min, width float64
+	n          int
+}
+
+func (l linearLayout) index(x float64) int {
+	i := math.Floor((x - l.min) / l.width)
+	if i >= float64(l.n) {
+		return l.n
+	}
+	return int(math.Max(i, -1))
+}
+
+func (l linearLayout) bounds(i int) (float64, float64) {
+	return l.min + float64(i)*l.width, l.min + float64(i+1)*l.width
+}
+
+func (l linearLayout) size() int {
+	return l.n
+}
+
+type exponentialLayout struct {
+	start, factor float64
+	n             int
+}
+
+func (l exponentialLayout) index(x float64) int {
+	if x < l.start {
+		return -1
+	}
+	f := math.Floor(math.Log(x/l.start) / math.Log(l.factor))
+	if f >= float64(l.n) {
+		return l.n
+	}
+	i := int(f)
+	// correct the rounding error of Log around the bucket boundaries
+	if lo, hi := l.bounds(i); x < lo {
+		i--
+	} else if x >= hi {
+		i++
+	}
+	return Min(i, l.n)
+}
+
+func (l exponentialLayout) bounds(i int) (float64, float64) {
+	lo := l.start * math.Pow(l.factor, float64(i))
+	return lo, lo * l.factor
+}
+
+func (l exponentialLayout) size() int {
+	return l.n
+}
+
+// hdrLayout is the log-linear layout of HdrHistogram. Values are measured in
+// units of lowest. The first 2^subBits buckets have a width of 1 unit, and
+// after that, every power of 2 range is split into 2^(subBits-1) buckets.
+type hdrLayout struct {
+	lowest  float64
+	subBits uint
+	n       int
+}
+
+func (l hdrLayout) index(x float64) int {
+	if x < 0 {
+		return -1
+	}
+	v := x / l.lowest
+	if v >= float64(math.MaxUint64) {
+		return l.n
+	}
+
+	u := uint64(v)
+	subCount := uint64(1) << l.subBits
+	if u < subCount {
+		return int(u)
+	}
+
+	half := subCount >> 1
+	e := uint(bits.Len64(u)) - l.subBits
+	i := subCount + uint64(e-1)*half + (u>>e - half)
+	if i >= uint64(l.n) {
+		return l.n
+	}
+	return int(i)
+}
+
+func (l hdrLayout) bounds(i int) (float64, float64) {
+	subCount := 1 << l.subBits
+	if i < subCount {
+		return float64(i) * l.lowest, float64(i+1) * l.lowest
+	}
+
+	half := subCount >> 1
+	e := (i-subCount)/half + 1
+	sub := (i-subCount)%half + half
+	return math.Ldexp(float64(sub), e) * l.lowest, math.Ldexp(float64(sub+1), e) * l.lowest
+}
+
+func (l hdrLayout) size() int {
+	return l.n
+}
+
+// Histogram counts samples into buckets, so that the distribution of an
+// unbounded stream can be summarized, merged and rendered in constant memory.
+//
+// Samples below the first bucket and beyond the last one are counted as
+// underflow and overflow. The exact minimum and maximum are tracked as well,
+// and NaN samples are ignored.
+type Histogram struct {
+	layout    layout
+	counts    []uint64
+	underflow uint64
+	overflow  uint64
+	total     uint64
+	sum       float64
+	min, max  float64
+}
+
+// NewLinearHistogram returns a Histogram with n buckets of the same width,
+// where the i-th bucket is [min+i*width, min+(i+1)*width).
+func NewLinearHistogram(min, width float64, n int) *Histogram {
+	if math.IsNaN(min) || math.IsInf(min, 0) || !(width > 0) || n <= 0 {
+		panic("number: invalid linear histogram layout")
+	}
+	return newHistogram(linearLayout{min, width, n})
+}
+
+// NewExponentialHistogram returns a Histogram with n buckets whose widths grow
+// exponentially, where the i-th bucket is [start*factor^i, start*factor^(i+1)).
+func NewExponentialHistogram(start, factor float64, n int) *Histogram {
+	if !(start > 0) || !(factor > 1) || n <= 0 {
+		panic("number: invalid exponential histogram layout")
+	}
+	return newHistogram(exponentialLayout{start, factor, n})
+}
+
+// NewHDRHistogram returns a Histogram with the log-linear buckets of
+// HdrHistogram, covering [0, highest] in units of lowest and keeping digits
+// significant decimal digits, i.e. the width of the bucket of any value v is at
+// most max(lowest, v/10^digits). digits must be in [1, 5].
+//
+// For example, NewHDRHistogram(1, 3.6e9, 3) tracks latencies in microseconds
+// up to an hour with 0.1% accuracy, in about 23k buckets.
+func NewHDRHistogram(lowest, highest float64, digits int) *Histogram {
+	if !(lowest > 0) || !(highest >= lowest) || digits < 1 || digits > 5 {
+		panic("number: invalid HDR histogram layout")
+	}
+
+	// 2^(subBits-1) buckets per power of 2 gives a relative width of at most
+	// 2^-(subBits-1), which must not exceed 10^-digits
+	subBits := uint(math.Ceil(math.Log2(2 * math.Pow10(digits))))
+	l := hdrLayout{lowest, subBits, math.MaxInt32}
+	l.n = l.index(highest) + 1
+
+	return newHistogram(l)
+}
+
+func newHistogram(l layout) *Histogram {
+	return &Histogram{
+		layout: l,
+		counts: make([]uint64, l.size()),
+		min:    math.Inf(1),
+		max:    math.Inf(-1),
+	}
+}
+
+// Record records a sample x.
+func (h *Histogram) Record(x float64) {
+	h.RecordN(x, 1)
+}
+
+// RecordN records a sample x n times.
+func (h *Histogram) RecordN(x float64, n uint64) {
+	if math.IsNaN(x) || n == 0 {
+		return
+	}
+
+	switch i := h.layout.index(x); {
+	case i < 0:
+		h.underflow += n
+	case i >= len(h.counts):
+		h.overflow += n
+	default:
+		h.counts[i] += n
+	}
+
+	h.total += n
+	h.sum += x * float64(n)
+	if x < h.min {
+		h.min = x
+	}
+	if x > h.max {
+		h.max = x
+	}
+}
+
+// Merge adds the samples of o to h. It fails with ErrHistogramMismatch if h
+// and o were not created with the same layout.
+func (h *Histogram) Merge(o *Histogram) error {
+	if h.layout != o.layout {
+		return ErrHistogramMismatch
+	}
+
+	for i, c := range o.counts {
+		h.counts[i] += c
+	}
+	h.underflow += o.underflow
+	h.overflow += o.overflow
+	h.total += o.total
+	h.sum += o.sum
+	if o.min < h.min {
+		h.min = o.min
+	}
+	if o.max > h.max {
+		h.max = o.max
+	}
+
+	return nil
+}
+
+// Reset removes all the samples from h.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.underflow, h.overflow, h.total, h.sum = 0, 0, 0, 0
+	h.min, h.max = math.Inf(1), math.Inf(-1)
+}
+
+// Count returns the number of samples, including underflow and overflow.
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Underflow returns the number of samples below the first bucket.
+func (h *Histogram) Underflow() uint64 {
+	return h.underflow
+}
+
+// Overflow returns the number of samples beyond the last bucket.
+func (h *Histogram) Overflow() uint64 {
+	return h.overflow
+}
+
+// Min returns the smallest sample, or NaN if h is empty.
+func (h *Histogram) Min() float64 {
+	if h.total == 0 {
+		return math.NaN()
+	}
+	return h.min
+}
+
+// Max returns the largest sample, or NaN if h is empty.
+func (h *Histogram) Max() float64 {
+	if h.total == 0 {
+		return math.NaN()
+	}
+	return h.max
+}
+
+// Mean returns the arithmetic mean of the samples, or NaN if h is empty.
+func (h *Histogram) Mean() float64 {
+	if h.total == 0 {
+		return math.NaN()
+	}
+	return h.sum / float64(h.total)
+}
+
+// Quantile returns the estimated q-th quantile, where q is in [0, 1], by
+// interpolating linearly within the bucket it falls in. The estimate is
+// clamped to [Min(), Max()], and so are the estimates within underflow and
+// overflow. It returns NaN if h is empty or q is out of range.
+func (h *Histogram) Quantile(q float64) float64 {
+	if h.total == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+
+	rank := q * float64(h.total)
+	seen := float64(h.underflow)
+	if rank <= seen && h.underflow > 0 {
+		return h.min
+	}
+
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		if next := seen + float64(c); rank <= next {
+			lo, hi := h.layout.bounds(i)
+			v := lo + (hi-lo)*(rank-seen)/float64(c)
+			return math.Max(h.min, math.Min(h.max, v))
+		}
+		seen += float64(c)
+	}
+
+	return h.max
+}
+
+// Percentile is like Quantile, but p is in [0, 100].
+func (h *Histogram) Percentile(p float64) float64 {
+	return h.Quantile(p / 100)
+}
+
+// HistogramBucket is a bucket of a Histogram.
+type HistogramBucket struct {
+	Lo, Hi float64 // [Lo, Hi)
+	Count  uint64
+}
+
+// Buckets returns the buckets from the first non-empty one to the last
+// non-empty one, excluding underflow and overflow.
+func (h *Histogram) Buckets() []HistogramBucket {
+	first, last := -1, -1
+	for i, c := range h.counts {
+		if c != 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+
+	b := make([]HistogramBucket, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		lo, hi := h.layout.bounds(i)
+		b = append(b, HistogramBucket{lo, hi, h.counts[i]})
+	}
+	return b
+}
+
+// Render writes a horizontal bar chart of h to w, fitting in width columns,
+// e.g. cli.TerminalWidth(). If maxRows is positive, adjacent buckets are
+// combined so that there are at most maxRows rows, excluding the underflow and
+// overflow rows.
+//
+// An example output:
+//     [  0,  10) 3     ######
+//     [ 10,  20) 12    ########################
+//     [ 20,  30) 5     ##########
+//     >= 30      1     ##
+func (h *Histogram) Render(w io.Writer, width, maxRows int) error {
+	type row struct {
+		label string
+		count uint64
+	}
+
+	buckets := h.Buckets()
+	if maxRows > 0 && len(buckets) > maxRows {
+		per := (len(buckets) + maxRows - 1) / maxRows
+		combined := make([]HistogramBucket, 0, maxRows)
+		for i := 0; i < len(buckets); i += per {
+			b := buckets[i]
+			for _, n := range buckets[i+1 : Min(i+per, len(buckets))] {
+				b.Hi = n.Hi
+				b.Count += n.Count
+			}
+			combined = append(combined, b)
+		}
+		buckets = combined
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	loWidth, hiWidth := 0, 0
+	for _, b := range buckets {
+		loWidth = Max(loWidth, len(format(b.Lo)))
+		hiWidth = Max(hiWidth, len(format(b.Hi)))
+	}
+
+	var rows []row
+	if h.underflow > 0 {
+		lo, _ := h.layout.bounds(0)
+		rows = append(rows, row{"< " + format(lo), h.underflow})
+	}
+	for _, b := range buckets {
+		label := fmt.Sprintf("[%*s, %*s)", loWidth, format(b.Lo), hiWidth, format(b.Hi))
+		rows = append(rows, row{label, b.Count})
+	}
+	if h.overflow > 0 {
+		_, hi := h.layout.bounds(h.layout.size() - 1)
+		rows = append(rows, row{">= " + format(hi), h.overflow})
+	}
+
+	labelWidth, countWidth := 0, 0
+	var maxCount uint64
+	for _, r := range rows {
+		labelWidth = Max(labelWidth, len(r.label))
+		countWidth = Max(countWidth, len(strconv.FormatUint(r.count, 10)))
+		maxCount = Max(maxCount, r.count)
+	}
+
+	barWidth := Max(width-labelWidth-countWidth-2, 1)
+	for _, r := range rows {
+		bar := int(float64(r.count) / float64(maxCount) * float64(barWidth))
+		if bar == 0 && r.count > 0 {
+			bar = 1
+		}
+		line := fmt.Sprintf("%-*s %-*d %s", labelWidth, r.label, countWidth, r.count, strings.Repeat("#", bar))
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
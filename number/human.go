@@ -0,0 +1,297 @@
+package number
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ByteSystem specifies the unit prefixes used by FormatBytes.
+type ByteSystem int
+
+const (
+	// IEC uses binary prefixes, 1 KiB = 1024 B.
+	IEC ByteSystem = iota
+	// SI uses decimal prefixes, 1 kB = 1000 B.
+	SI
+)
+
+var (
+	iecByteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siByteUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+type siPrefix struct {
+	symbol string
+	exp    int // power of 1000
+}
+
+var siPrefixes = []siPrefix{
+	{"y", -8}, {"z", -7}, {"a", -6}, {"f", -5}, {"p", -4}, {"n", -3}, {"µ", -2}, {"m", -1},
+	{"", 0},
+	{"k", 1}, {"M", 2}, {"G", 3}, {"T", 4}, {"P", 5}, {"E", 6}, {"Z", 7}, {"Y", 8},
+}
+
+// FormatBytes formats a size in bytes with the largest fitting unit of sys and
+// 3 significant digits, e.g. "0 B", "999 B", "1.5 KiB" or "20.3 MB". Like
+// sizes in ls -lh, the integer part is never rounded, so there may be more
+// than 3 digits, e.g. "1023 KiB".
+func FormatBytes(n int64, sys ByteSystem) string {
+	units, base := iecByteUnits, 1024.0
+	if sys == SI {
+		units, base = siByteUnits, 1000
+	}
+
+	return formatScaled(float64(n), base, units)
+}
+
+// FormatSI formats v with the SI prefix that gives an integer part in [1,
+// 1000), and 3 significant digits, followed by unit, e.g. FormatSI(1500, "Hz")
+// is "1.5 kHz" and FormatSI(0.00025, "s") is "250 µs". Prefixes range from y
+// (10^-24) to Y (10^24).
+func FormatSI(v float64, unit string) string {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return strings.TrimSpace(strconv.FormatFloat(v, 'g', -1, 64) + " " + unit)
+	}
+
+	exp := int(math.Floor(math.Log10(math.Abs(v)) / 3))
+	exp = Max(Min(exp, 8), -8)
+	units := make([]string, 0, len(siPrefixes))
+	for _, p := range siPrefixes[exp+8:] {
+		units = append(units, strings.TrimSpace(p.symbol+unit))
+	}
+
+	s := formatScaled(v/math.Pow(1000, float64(exp)), 1000, units)
+	return strings.TrimSpace(s)
+}
+
+// formatScaled divides v by base until its magnitude is less than base, and
+// formats it with the corresponding unit.
+func formatScaled(v, base float64, units []string) string {
+	for i, unit := range units {
+		r := v
+		if a := math.Abs(v); a >= 1 {
+			r = RoundWith(v, Max(0, 2-int(math.Floor(math.Log10(a)))), RoundHalfEven)
+		} else if a > 0 {
+			r = RoundSig(v, 3, RoundHalfEven)
+		}
+
+		if math.Abs(r) < base || i == len(units)-1 {
+			return strings.TrimRight(strconv.FormatFloat(r, 'f', -1, 64)+" "+unit, " ")
+		}
+		v /= base
+	}
+
+	// unreachable
+	return ""
+}
+
+// Locale specifies the separators used by FormatGrouped and FormatIntGrouped.
+type Locale struct {
+	Group   string // thousands separator
+	Decimal string // decimal separator
+}
+
+var (
+	LocaleEnglish = Locale{",", "."}
+	LocaleGerman  = Locale{".", ","}
+	LocaleFrench  = Locale{" ", ","} // narrow no-break space
+	LocaleSwiss   = Locale{"'", "."}
+	LocaleSI      = Locale{" ", "."} // thin space
+)
+
+// FormatGrouped formats v like strconv.FormatFloat(v, 'f', prec, 64), but with
+// the separators of loc, e.g. FormatGrouped(1234567.891, 2, LocaleEnglish) is
+// "1,234,567.89".
+func FormatGrouped(v float64, prec int, loc Locale) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	s = groupDigits(intPart, loc.Group)
+	if fracPart != "" {
+		s += loc.Decimal + fracPart
+	}
+	return s
+}
+
+// FormatIntGrouped formats n in base 10 with the thousands separator of loc,
+// e.g. FormatIntGrouped(-1234567, LocaleGerman) is "-1.234.567".
+func FormatIntGrouped[T Integer](n T, loc Locale) string {
+	if isSigned[T]() {
+		return groupDigits(strconv.FormatInt(int64(n), 10), loc.Group)
+	}
+	return groupDigits(strconv.FormatUint(uint64(n), 10), loc.Group)
+}
+
+// groupDigits inserts sep between every 3 digits of an optionally signed
+// integer s.
+func groupDigits(s, sep string) string {
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	head := len(s) % 3
+	if head == 0 {
+		head = 3
+	}
+	b.WriteString(s[:head])
+	for i := head; i < len(s); i += 3 {
+		b.WriteString(sep)
+		b.WriteString(s[i : i+3])
+	}
+
+	return b.String()
+}
+
+// splitNumber splits s into a leading decimal number, possibly with a sign and
+// an exponent, and the rest with leading spaces trimmed.
+func splitNumber(s string) (num, rest string) {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := 0
+	for ; i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.'); i++ {
+		if s[i] != '.' {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return "", s
+	}
+
+	// an exponent only if there are digits after it, so that "1E" is 1 exa
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		k := j
+		for k < len(s) && s[k] >= '0' && s[k] <= '9' {
+			k++
+		}
+		if k > j {
+			i = k
+		}
+	}
+
+	return s[:i], strings.TrimLeft(s[i:], " ")
+}
+
+// ParseBytes parses a size in bytes, such as "1.5GiB", "20k", "3e6", "512" or
+// "10 MB". The unit is case-insensitive and optional; K, M, G, T, P and E are
+// decimal (SI) prefixes, while Ki, Mi, Gi, Ti, Pi and Ei are binary (IEC) ones,
+// and the trailing B is optional. The result is rounded to the nearest
+// integer. Negative sizes are rejected.
+func ParseBytes(s string) (int64, error) {
+	num, unit := splitNumber(strings.TrimSpace(s))
+	d, err := ParseDecimal(num)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("parsing size %q: %w", s, strconv.ErrRange)
+		}
+		return 0, fmt.Errorf("parsing size %q: %w", s, strconv.ErrSyntax)
+	}
+	if d.Sign() < 0 {
+		return 0, fmt.Errorf("parsing size %q: negative size", s)
+	}
+
+	u := strings.ToLower(unit)
+	u = strings.TrimSuffix(u, "b")
+	binary := strings.HasSuffix(u, "i")
+	u = strings.TrimSuffix(u, "i")
+
+	exp := 0
+	if u != "" {
+		exp = strings.Index("kmgtpe", u) + 1
+		if len(u) > 1 || exp == 0 {
+			return 0, fmt.Errorf("parsing size %q: unknown unit %q", s, unit)
+		}
+	} else if binary {
+		return 0, fmt.Errorf("parsing size %q: unknown unit %q", s, unit)
+	}
+
+	// d < 10^mag. Check it before scaling, which is slow for an exponent such
+	// as the one of "1e99999": anything from 10^40 overflows int64 even
+	// without a prefix, and anything below 10^-40 rounds to 0 even with the
+	// largest prefix.
+	if d.IsZero() {
+		return 0, nil
+	}
+	switch mag := d.Exponent() + numDigits(d.coefficient()); {
+	case mag > 40:
+		return 0, fmt.Errorf("parsing size %q: %w", s, strconv.ErrRange)
+	case mag < -40:
+		return 0, nil
+	}
+
+	var mult *big.Int
+	if binary {
+		mult = new(big.Int).Lsh(big.NewInt(1), uint(10*exp))
+	} else {
+		mult = pow10(3 * exp)
+	}
+	d = d.Mul(NewDecimalFromBigInt(mult, 0)).Round(0, RoundHalfEven)
+
+	c := d.Coefficient()
+	if !c.IsInt64() {
+		return 0, fmt.Errorf("parsing size %q: %w", s, strconv.ErrRange)
+	}
+	return c.Int64(), nil
+}
+
+// ParseSI parses a number with an optional SI prefix, such as "20k", "1.5 M",
+// "3e6" or "250µ". Prefixes are case-sensitive, e.g. "m" is milli and "M" is
+// mega, except that "K" is accepted for kilo, and "u" for micro.
+func ParseSI(s string) (float64, error) {
+	num, prefix := splitNumber(strings.TrimSpace(s))
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("parsing %q: %w", s, strconv.ErrRange)
+		}
+		return 0, fmt.Errorf("parsing %q: %w", s, strconv.ErrSyntax)
+	}
+
+	switch prefix {
+	case "K":
+		prefix = "k"
+	case "u", "μ": // Greek small letter mu
+		prefix = "µ"
+	}
+	if utf8.RuneCountInString(prefix) > 1 {
+		return 0, fmt.Errorf("parsing %q: unknown prefix %q", s, prefix)
+	}
+	for _, p := range siPrefixes {
+		if p.symbol != prefix {
+			continue
+		}
+		// divide instead of multiplying by an inexact negative power of 10
+		if p.exp < 0 {
+			return v / math.Pow(1000, float64(-p.exp)), nil
+		}
+		if r := v * math.Pow(1000, float64(p.exp)); !math.IsInf(r, 0) {
+			return r, nil
+		}
+		return 0, fmt.Errorf("parsing %q: %w", s, strconv.ErrRange)
+	}
+
+	return 0, fmt.Errorf("parsing %q: unknown prefix %q", s, prefix)
+}
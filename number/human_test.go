@@ -0,0 +1,88 @@
+package number
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int64
+		err  error
+	}{
+		{"512", 512, nil},
+		{"1.5GiB", 3 << 29, nil},
+		{"20k", 20000, nil},
+		{"3e6", 3000000, nil},
+		{"10 MB", 10000000, nil},
+		{"2.5", 2, nil},
+		{"0", 0, nil},
+		{"-0", 0, nil},
+		{"0e99999", 0, nil},
+		{"1e-99999", 0, nil},
+		{"1e-50EiB", 0, nil},
+		{"8EiB", 0, strconv.ErrRange},
+		{"9223372036854775807", math.MaxInt64, nil},
+		{"9223372036854775808", 0, strconv.ErrRange},
+		{"1e41", 0, strconv.ErrRange},
+		{"1e99999", 0, strconv.ErrRange},
+		{"1e999999999", 0, strconv.ErrRange},
+		{"abc", 0, strconv.ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBytes(tt.s)
+		switch {
+		case tt.err != nil:
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseBytes(%q) error = %v, want %v", tt.s, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("ParseBytes(%q): %v", tt.s, err)
+		case got != tt.want:
+			t.Errorf("ParseBytes(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"-5", "-1KiB", "-0.1", "5XB", "5iB"} {
+		if _, err := ParseBytes(s); err == nil {
+			t.Errorf("ParseBytes(%q) succeeded", s)
+		}
+	}
+}
+
+func TestParseSI(t *testing.T) {
+	tests := []struct {
+		s    string
+		want float64
+		err  error
+	}{
+		{"20k", 20000, nil},
+		{"1.5 M", 1.5e6, nil},
+		{"3e6", 3e6, nil},
+		{"250µ", 250e-6, nil},
+		{"1e999", 0, strconv.ErrRange},
+		{"1e999k", 0, strconv.ErrRange},
+		{"1e308k", 0, strconv.ErrRange},
+		{"-1e308Y", 0, strconv.ErrRange},
+		{"k", 0, strconv.ErrSyntax},
+		{"1.2.3", 0, strconv.ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSI(tt.s)
+		switch {
+		case tt.err != nil:
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseSI(%q) error = %v, want %v", tt.s, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("ParseSI(%q): %v", tt.s, err)
+		case math.Abs(got-tt.want) > 1e-12*math.Abs(tt.want):
+			t.Errorf("ParseSI(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package number
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// GCD returns the greatest common divisor of a and b, which is always
+// non-negative. GCD(0, 0) is 0. For a signed T, the result overflows if it
+// would be the negation of the smallest value of T, e.g. GCD(math.MinInt64, 0).
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// LCM returns the least common multiple of a and b, which is always
+// non-negative. LCM(a, 0) is 0. ok is false if the result overflows.
+func LCM[T Integer](a, b T) (l T, ok bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	l, ok = MulChecked(a/GCD(a, b), b)
+	if l < 0 {
+		return -l, ok && l != minOf[T]()
+	}
+	return l, ok
+}
+
+// ExtendedGCD returns the greatest common divisor g of a and b along with the
+// Bézout coefficients x and y, such that a*x + b*y = g. g is non-negative.
+func ExtendedGCD[T Signed](a, b T) (g, x, y T) {
+	oldR, r := a, b
+	oldX, x := T(1), T(0)
+	oldY, y := T(0), T(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldX, x = x, oldX-q*x
+		oldY, y = y, oldY-q*y
+	}
+
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// mulMod returns a*b mod m without overflowing. m must not be 0.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, r := bits.Div64(hi%m, lo, m)
+	return r
+}
+
+// ModPow returns base^exp mod m, which is in [0, m). It panics if m is not
+// positive or exp is negative.
+func ModPow[T Integer](base, exp, m T) T {
+	if m <= 0 {
+		panic("number: ModPow with non-positive modulus")
+	}
+	if exp < 0 {
+		panic("number: ModPow with negative exponent")
+	}
+
+	mod := uint64(m)
+	b := uint64(floorMod(base, m))
+	r := uint64(1) % mod
+	for e := uint64(exp); e != 0; e >>= 1 {
+		if e&1 == 1 {
+			r = mulMod(r, b, mod)
+		}
+		b = mulMod(b, b, mod)
+	}
+
+	return T(r)
+}
+
+// ModInverse returns x in [0, m) such that a*x mod m is 1. ok is false if a and
+// m are not coprime. It panics if m is not positive.
+func ModInverse[T Integer](a, m T) (x T, ok bool) {
+	if m <= 0 {
+		panic("number: ModInverse with non-positive modulus")
+	}
+
+	// use math/big rather than ExtendedGCD, since the intermediates of the
+	// latter may overflow for unsigned or large T
+	var bm, ba big.Int
+	if isSigned[T]() {
+		bm.SetInt64(int64(m))
+		ba.SetInt64(int64(floorMod(a, m)))
+	} else {
+		bm.SetUint64(uint64(m))
+		ba.SetUint64(uint64(a % m))
+	}
+	inv := new(big.Int).ModInverse(&ba, &bm)
+	if inv == nil {
+		return 0, false
+	}
+	return T(inv.Uint64()), true
+}
+
+// Sqrt returns the integer square root of n, i.e. the largest r such that
+// r*r <= n. It is exact for the whole range of T, unlike a conversion from
+// math.Sqrt. It panics if n is negative.
+func Sqrt[T Integer](n T) T {
+	if n < 0 {
+		panic("number: square root of negative number")
+	}
+
+	u := uint64(n)
+	if u < 2 {
+		return n
+	}
+
+	// Newton's method from an initial guess >= the root
+	r := uint64(1) << ((uint(bits.Len64(u)) + 1) / 2)
+	for {
+		next := (r + u/r) / 2
+		if next >= r {
+			return T(r)
+		}
+		r = next
+	}
+}
+
+// Log2 returns floor(log2(n)). It panics if n is not positive.
+func Log2[T Integer](n T) int {
+	if n <= 0 {
+		panic("number: Log2 of non-positive number")
+	}
+	return bits.Len64(uint64(n)) - 1
+}
+
+var powersOf10 = [...]uint64{
+	1, 10, 100, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19,
+}
+
+// Log10 returns floor(log10(n)). It panics if n is not positive.
+func Log10[T Integer](n T) int {
+	if n <= 0 {
+		panic("number: Log10 of non-positive number")
+	}
+
+	u := uint64(n)
+	// log10(2) ~= 1233/4096
+	l := (bits.Len64(u) * 1233) >> 12
+	if l < len(powersOf10) && u < powersOf10[l] {
+		l--
+	}
+	return l
+}
+
+// IsPowerOfTwo reports whether n is a power of 2. It is false for n <= 0.
+func IsPowerOfTwo[T Integer](n T) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// NextPowerOfTwo returns the smallest power of 2 that is >= n. It returns 1
+// for n <= 1. ok is false if the result overflows T.
+func NextPowerOfTwo[T Integer](n T) (p T, ok bool) {
+	if n <= 1 {
+		return 1, true
+	}
+
+	p = T(1) << uint(bits.Len64(uint64(n-1)))
+	return p, p > 0
+}
+
+// CeilDiv returns a/b rounded toward +Inf. It panics if b is 0.
+func CeilDiv[T Integer](a, b T) T {
+	q := a / b
+	// a/b truncates toward 0, which is the ceiling iff the exact quotient is
+	// negative or exact
+	if a%b != 0 && (a < 0) == (b < 0) {
+		q++
+	}
+	return q
+}
+
+// FloorDiv returns a/b rounded toward -Inf. It panics if b is 0.
+func FloorDiv[T Integer](a, b T) T {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns a mod m with the sign of m, e.g. floorMod(-1, 3) is 2.
+func floorMod[T Integer](a, m T) T {
+	r := a % m
+	if r != 0 && (r < 0) != (m < 0) {
+		r += m
+	}
+	return r
+}
@@ -0,0 +1,228 @@
+package number
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Interval is a half-open interval [Lo, Hi), i.e. containing every x such that
+// Lo <= x < Hi. Half-open intervals compose well: [a, b) and [b, c) are
+// adjacent without overlapping, and the length is simply Hi-Lo. The inclusive
+// page range 1-5 is Interval[int]{1, 6}, and the first 100 bytes of a file are
+// Interval[int64]{0, 100}.
+//
+// An interval with Hi <= Lo, including the zero value, is empty.
+type Interval[T Real] struct {
+	Lo, Hi T
+}
+
+// IsEmpty reports whether iv contains nothing, i.e. Hi <= Lo, or either end is
+// NaN.
+func (iv Interval[T]) IsEmpty() bool {
+	return !(iv.Lo < iv.Hi)
+}
+
+// Len returns Hi-Lo, or 0 if iv is empty.
+func (iv Interval[T]) Len() T {
+	if iv.IsEmpty() {
+		return 0
+	}
+	return iv.Hi - iv.Lo
+}
+
+// Contains reports whether x is in iv.
+func (iv Interval[T]) Contains(x T) bool {
+	return iv.Lo <= x && x < iv.Hi
+}
+
+// Overlaps reports whether iv and o have anything in common. Adjacent intervals
+// such as [0, 5) and [5, 10) do not overlap.
+func (iv Interval[T]) Overlaps(o Interval[T]) bool {
+	return !iv.Intersect(o).IsEmpty()
+}
+
+// Intersect returns the intersection of iv and o, which is the zero Interval if
+// they do not overlap.
+func (iv Interval[T]) Intersect(o Interval[T]) Interval[T] {
+	r := Interval[T]{Max(iv.Lo, o.Lo), Min(iv.Hi, o.Hi)}
+	if r.IsEmpty() {
+		return Interval[T]{}
+	}
+	return r
+}
+
+// Union returns the smallest interval that covers both iv and o. ok is false if
+// iv and o neither overlap nor are adjacent, since their union is not an
+// interval then; use IntervalSet for that. An empty interval is ignored.
+func (iv Interval[T]) Union(o Interval[T]) (u Interval[T], ok bool) {
+	switch {
+	case iv.IsEmpty():
+		return o, true
+	case o.IsEmpty():
+		return iv, true
+	case iv.Hi < o.Lo || o.Hi < iv.Lo:
+		return Interval[T]{}, false
+	}
+	return Interval[T]{Min(iv.Lo, o.Lo), Max(iv.Hi, o.Hi)}, true
+}
+
+// String returns iv in the form of "[Lo, Hi)".
+func (iv Interval[T]) String() string {
+	return fmt.Sprintf("[%v, %v)", iv.Lo, iv.Hi)
+}
+
+// IntervalSet is a set of numbers represented as a sorted list of disjoint
+// intervals. It is always normalized: overlapping or adjacent intervals are
+// merged as they are added, e.g. adding [0, 5), [10, 20) and [5, 8) results in
+// [0, 8) and [10, 20).
+//
+// The zero value is an empty set ready to use. An IntervalSet must not be
+// copied after first use.
+//
+// An example of tracking downloaded byte ranges:
+//     var done number.IntervalSet[int64]
+//     done.Add(number.Interval[int64]{0, 4096})
+//     done.Add(number.Interval[int64]{8192, 16384})
+//     missing := done.Gaps(number.Interval[int64]{0, size})
+type IntervalSet[T Real] struct {
+	ivs []Interval[T]
+}
+
+// search returns the index of the first interval in s whose Hi satisfies f.
+func (s *IntervalSet[T]) search(f func(hi T) bool) int {
+	return sort.Search(len(s.ivs), func(i int) bool { return f(s.ivs[i].Hi) })
+}
+
+// Add adds all the numbers in iv to s. Adding an empty interval is a no-op.
+func (s *IntervalSet[T]) Add(iv Interval[T]) {
+	if iv.IsEmpty() {
+		return
+	}
+
+	// s.ivs[i:j] are the intervals that overlap or touch iv
+	i := s.search(func(hi T) bool { return hi >= iv.Lo })
+	j := i + sort.Search(len(s.ivs)-i, func(k int) bool { return s.ivs[i+k].Lo > iv.Hi })
+	if i == j {
+		s.ivs = append(s.ivs, Interval[T]{})
+		copy(s.ivs[i+1:], s.ivs[i:])
+		s.ivs[i] = iv
+		return
+	}
+
+	iv.Lo = Min(iv.Lo, s.ivs[i].Lo)
+	iv.Hi = Max(iv.Hi, s.ivs[j-1].Hi)
+	s.ivs[i] = iv
+	s.ivs = append(s.ivs[:i+1], s.ivs[j:]...)
+}
+
+// Remove removes all the numbers in iv from s, splitting an interval of s in
+// two if iv is strictly inside it.
+func (s *IntervalSet[T]) Remove(iv Interval[T]) {
+	if iv.IsEmpty() {
+		return
+	}
+
+	// s.ivs[i:j] are the intervals that overlap iv
+	i := s.search(func(hi T) bool { return hi > iv.Lo })
+	j := i + sort.Search(len(s.ivs)-i, func(k int) bool { return s.ivs[i+k].Lo >= iv.Hi })
+	if i == j {
+		return
+	}
+
+	var rest []Interval[T]
+	if first := s.ivs[i]; first.Lo < iv.Lo {
+		rest = append(rest, Interval[T]{first.Lo, iv.Lo})
+	}
+	if last := s.ivs[j-1]; iv.Hi < last.Hi {
+		rest = append(rest, Interval[T]{iv.Hi, last.Hi})
+	}
+	s.ivs = append(s.ivs[:i], append(rest, s.ivs[j:]...)...)
+}
+
+// Reset removes everything from s.
+func (s *IntervalSet[T]) Reset() {
+	s.ivs = s.ivs[:0]
+}
+
+// IsEmpty reports whether s contains nothing.
+func (s *IntervalSet[T]) IsEmpty() bool {
+	return len(s.ivs) == 0
+}
+
+// Contains reports whether x is in s.
+func (s *IntervalSet[T]) Contains(x T) bool {
+	i := s.search(func(hi T) bool { return hi > x })
+	return i < len(s.ivs) && s.ivs[i].Lo <= x
+}
+
+// ContainsInterval reports whether every number in iv is in s. It is true for
+// an empty iv.
+func (s *IntervalSet[T]) ContainsInterval(iv Interval[T]) bool {
+	if iv.IsEmpty() {
+		return true
+	}
+
+	i := s.search(func(hi T) bool { return hi > iv.Lo })
+	return i < len(s.ivs) && s.ivs[i].Lo <= iv.Lo && iv.Hi <= s.ivs[i].Hi
+}
+
+// Overlaps reports whether any number in iv is in s.
+func (s *IntervalSet[T]) Overlaps(iv Interval[T]) bool {
+	if iv.IsEmpty() {
+		return false
+	}
+
+	i := s.search(func(hi T) bool { return hi > iv.Lo })
+	return i < len(s.ivs) && s.ivs[i].Lo < iv.Hi
+}
+
+// Intervals returns the disjoint, non-adjacent intervals of s in ascending
+// order. The returned slice is a copy.
+func (s *IntervalSet[T]) Intervals() []Interval[T] {
+	ivs := make([]Interval[T], len(s.ivs))
+	copy(ivs, s.ivs)
+	return ivs
+}
+
+// Total returns the sum of the lengths of all intervals in s, e.g. the number
+// of bytes downloaded.
+func (s *IntervalSet[T]) Total() T {
+	var sum T
+	for _, iv := range s.ivs {
+		sum += iv.Len()
+	}
+	return sum
+}
+
+// Gaps returns the intervals in within that are not in s, in ascending order,
+// e.g. the byte ranges still to be downloaded.
+func (s *IntervalSet[T]) Gaps(within Interval[T]) []Interval[T] {
+	if within.IsEmpty() {
+		return nil
+	}
+
+	var gaps []Interval[T]
+	lo := within.Lo
+	i := s.search(func(hi T) bool { return hi > within.Lo })
+	for ; i < len(s.ivs) && s.ivs[i].Lo < within.Hi; i++ {
+		if lo < s.ivs[i].Lo {
+			gaps = append(gaps, Interval[T]{lo, s.ivs[i].Lo})
+		}
+		lo = s.ivs[i].Hi
+	}
+	if lo < within.Hi {
+		gaps = append(gaps, Interval[T]{lo, within.Hi})
+	}
+
+	return gaps
+}
+
+// String returns s in the form of "{[0, 8), [10, 20)}".
+func (s *IntervalSet[T]) String() string {
+	parts := make([]string, len(s.ivs))
+	for i, iv := range s.ivs {
+		parts[i] = iv.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,85 @@
+package number
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestIntervalSet checks random Adds and Removes against a bitmap of [0, 64).
+func TestIntervalSet(t *testing.T) {
+	const size = 64
+	rng := rand.New(rand.NewSource(1))
+	randInterval := func() Interval[int] {
+		// sometimes empty or reversed
+		return Interval[int]{rng.Intn(size + 1), rng.Intn(size + 1)}
+	}
+
+	for round := 0; round < 200; round++ {
+		var s IntervalSet[int]
+		var model [size]bool
+
+		for op := 0; op < 50; op++ {
+			iv := randInterval()
+			add := rng.Intn(3) > 0
+			if add {
+				s.Add(iv)
+			} else {
+				s.Remove(iv)
+			}
+			for x := iv.Lo; x < iv.Hi; x++ {
+				model[x] = add
+			}
+
+			ivs := s.Intervals()
+			total := 0
+			for k, v := range ivs {
+				if v.IsEmpty() || k > 0 && ivs[k-1].Hi >= v.Lo {
+					t.Fatalf("after %d ops: intervals %v not sorted, non-empty and non-adjacent", op+1, ivs)
+				}
+				total += v.Len()
+			}
+
+			count := 0
+			for x := -2; x < size+2; x++ {
+				want := x >= 0 && x < size && model[x]
+				if want {
+					count++
+				}
+				if got := s.Contains(x); got != want {
+					t.Fatalf("after %d ops: %v Contains(%d) = %v, want %v", op+1, &s, x, got, want)
+				}
+			}
+			if total != count || s.Total() != count {
+				t.Fatalf("after %d ops: %v Total() = %d, want %d", op+1, &s, s.Total(), count)
+			}
+			if s.IsEmpty() != (count == 0) {
+				t.Fatalf("after %d ops: %v IsEmpty() = %v", op+1, &s, s.IsEmpty())
+			}
+
+			q := randInterval()
+			all, some := true, false
+			for x := q.Lo; x < q.Hi; x++ {
+				all = all && model[x]
+				some = some || model[x]
+			}
+			if got := s.ContainsInterval(q); got != all {
+				t.Fatalf("%v ContainsInterval(%v) = %v, want %v", &s, q, got, all)
+			}
+			if got := s.Overlaps(q); got != some {
+				t.Fatalf("%v Overlaps(%v) = %v, want %v", &s, q, got, some)
+			}
+
+			var gapModel [size]bool
+			for _, g := range s.Gaps(Interval[int]{0, size}) {
+				for x := g.Lo; x < g.Hi; x++ {
+					gapModel[x] = true
+				}
+			}
+			for x := range model {
+				if gapModel[x] == model[x] {
+					t.Fatalf("%v Gaps([0, %d)) wrong at %d", &s, size, x)
+				}
+			}
+		}
+	}
+}
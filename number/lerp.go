@@ -0,0 +1,29 @@
+package number
+
+// Lerp linearly interpolates between a and b, i.e. returns a + (b-a)*t. It is
+// exact at both ends, so Lerp(a, b, 0) is a and Lerp(a, b, 1) is b, and
+// monotonic in t. t is not clamped, so values outside [0, 1] extrapolate.
+func Lerp[T Float](a, b, t T) T {
+	d := b - a
+	if t >= 0.5 {
+		return b - d*(1-t)
+	}
+	return a + d*t
+}
+
+// InverseLerp is the inverse of Lerp, i.e. returns t such that Lerp(a, b, t)
+// is v. The result is not clamped, so it is outside [0, 1] if v is outside
+// [a, b]. InverseLerp(a, a, v) is 0.
+func InverseLerp[T Float](a, b, v T) T {
+	if a == b {
+		return 0
+	}
+	return (v - a) / (b - a)
+}
+
+// MapRange maps v linearly from the range [inMin, inMax] to [outMin, outMax],
+// e.g. MapRange(5, 0, 10, 0, 100) is 50. Either range can be reversed, i.e.
+// have min > max. The result is not clamped; wrap v in Clamp for that.
+func MapRange[T Float](v, inMin, inMax, outMin, outMax T) T {
+	return Lerp(outMin, outMax, InverseLerp(inMin, inMax, v))
+}
@@ -0,0 +1,113 @@
+package number
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaturalCompare compares a and b in natural order, where runs of ASCII digits
+// are compared by their numeric values rather than character by character, so
+// "img2.png" < "img10.png" < "img10b.png". It returns -1, 0 or +1 like
+// strings.Compare.
+//
+// Digit runs of any length are compared without converting them to integers,
+// so they never overflow. Numerically equal runs with different numbers of
+// leading zeros, such as "1" and "01", are tied, and the tie is broken by the
+// first such run in favor of fewer leading zeros, and then by strings.Compare,
+// so that the result is 0 only if a == b. Other characters are compared by
+// their code points.
+func NaturalCompare(a, b string) int {
+	return naturalCompare(a, b, false)
+}
+
+// NaturalCompareFold is like NaturalCompare, but compares characters under
+// Unicode simple case folding, so "File10" and "file10" are tied, and then
+// ordered by strings.Compare.
+func NaturalCompareFold(a, b string) int {
+	return naturalCompare(a, b, true)
+}
+
+// NaturalLess reports whether a < b in natural order, i.e.
+// NaturalCompare(a, b) < 0, for use with sort.Slice.
+func NaturalLess(a, b string) bool {
+	return NaturalCompare(a, b) < 0
+}
+
+func naturalCompare(a, b string, fold bool) int {
+	tie := 0
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+
+			// without leading zeros, a longer run is a larger number, and
+			// runs of the same length compare lexically
+			x, y := strings.TrimLeft(a[si:i], "0"), strings.TrimLeft(b[sj:j], "0")
+			if c := compareInt(len(x), len(y)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+			if tie == 0 {
+				tie = compareInt(i-si, j-sj)
+			}
+			continue
+		}
+
+		x, n := utf8.DecodeRuneInString(a[i:])
+		y, m := utf8.DecodeRuneInString(b[j:])
+		if fold {
+			x, y = foldRune(x), foldRune(y)
+		}
+		if x != y {
+			return compareInt(int(x), int(y))
+		}
+		i += n
+		j += m
+	}
+
+	switch {
+	case i < len(a):
+		return 1
+	case j < len(b):
+		return -1
+	case tie != 0:
+		return tie
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func compareInt(x, y int) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// foldRune maps all the runes that are equivalent under simple case folding to
+// the same one, the smallest lower case one if there is one.
+func foldRune(r rune) rune {
+	best := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		lf, lb := unicode.IsLower(f), unicode.IsLower(best)
+		if lf && !lb || lf == lb && f < best {
+			best = f
+		}
+	}
+	return best
+}
@@ -0,0 +1,197 @@
+package number
+
+import (
+	"unsafe"
+)
+
+// isSigned reports whether T is a signed integer type.
+func isSigned[T Integer]() bool {
+	var zero T
+	return ^zero < 0
+}
+
+// maxOf returns the largest value of T.
+func maxOf[T Integer]() T {
+	var zero T
+	if isSigned[T]() {
+		return ^minOf[T]()
+	}
+	return ^zero
+}
+
+// minOf returns the smallest value of T.
+func minOf[T Integer]() T {
+	var zero T
+	if isSigned[T]() {
+		return T(1) << (unsafe.Sizeof(zero)*8 - 1)
+	}
+	return zero
+}
+
+// AddChecked returns a + b. ok is false if the result overflows, in which case
+// the returned value has wrapped around.
+func AddChecked[T Integer](a, b T) (s T, ok bool) {
+	s = a + b
+	if isSigned[T]() {
+		return s, (b > 0) == (s > a) || b == 0
+	}
+	return s, s >= a
+}
+
+// SubChecked returns a - b. ok is false if the result overflows, in which case
+// the returned value has wrapped around.
+func SubChecked[T Integer](a, b T) (d T, ok bool) {
+	d = a - b
+	if isSigned[T]() {
+		return d, (b > 0) == (d < a) || b == 0
+	}
+	return d, a >= b
+}
+
+// MulChecked returns a * b. ok is false if the result overflows, in which case
+// the returned value has wrapped around.
+func MulChecked[T Integer](a, b T) (p T, ok bool) {
+	p = a * b
+	if a == 0 || b == 0 {
+		return p, true
+	}
+	if isSigned[T]() {
+		// p / b below cannot detect this case, since MinInt * -1 wraps to
+		// MinInt and MinInt / -1 is MinInt as well
+		if lo, neg1 := minOf[T](), ^T(0); (a == neg1 && b == lo) || (b == neg1 && a == lo) {
+			return p, false
+		}
+	}
+	return p, p/b == a
+}
+
+// DivChecked returns a / b, truncated toward zero. ok is false if b is 0, or if
+// a is the smallest value of a signed T and b is -1, whose quotient overflows.
+// The returned value is 0 in the former case and a in the latter.
+func DivChecked[T Integer](a, b T) (q T, ok bool) {
+	if b == 0 {
+		return 0, false
+	}
+	if isSigned[T]() && a == minOf[T]() && b == ^T(0) {
+		return a, false
+	}
+	return a / b, true
+}
+
+// AddSat returns a + b, clamped to the bounds of T.
+func AddSat[T Integer](a, b T) T {
+	if s, ok := AddChecked(a, b); ok {
+		return s
+	}
+	if isSigned[T]() && b < 0 {
+		return minOf[T]()
+	}
+	return maxOf[T]()
+}
+
+// SubSat returns a - b, clamped to the bounds of T.
+func SubSat[T Integer](a, b T) T {
+	if d, ok := SubChecked(a, b); ok {
+		return d
+	}
+	if isSigned[T]() && b < 0 {
+		return maxOf[T]()
+	}
+	return minOf[T]()
+}
+
+// MulSat returns a * b, clamped to the bounds of T.
+func MulSat[T Integer](a, b T) T {
+	if p, ok := MulChecked(a, b); ok {
+		return p
+	}
+	if (a < 0) != (b < 0) {
+		return minOf[T]()
+	}
+	return maxOf[T]()
+}
+
+// DivSat returns a / b, clamped to the bounds of T. If b is 0, the result is
+// the largest value of T, the smallest value of T or 0 depending on the sign of
+// a.
+func DivSat[T Integer](a, b T) T {
+	if q, ok := DivChecked(a, b); ok {
+		return q
+	}
+	switch {
+	case b != 0, a > 0:
+		return maxOf[T]()
+	case a < 0:
+		return minOf[T]()
+	}
+	return 0
+}
+
+// ConvertChecked converts v to To. ok is false if v is out of the range of To,
+// in which case the returned value is the result of the plain conversion
+// To(v).
+func ConvertChecked[To, From Integer](v From) (t To, ok bool) {
+	t = To(v)
+	return t, From(t) == v && (t < 0) == (v < 0)
+}
+
+// ConvertSat converts v to To, clamped to the bounds of To.
+func ConvertSat[To, From Integer](v From) To {
+	if t, ok := ConvertChecked[To](v); ok {
+		return t
+	}
+	if v < 0 {
+		return minOf[To]()
+	}
+	return maxOf[To]()
+}
+
+// ToIntChecked is ConvertChecked to int.
+func ToIntChecked[T Integer](v T) (int, bool) {
+	return ConvertChecked[int](v)
+}
+
+// ToInt8Checked is ConvertChecked to int8.
+func ToInt8Checked[T Integer](v T) (int8, bool) {
+	return ConvertChecked[int8](v)
+}
+
+// ToInt16Checked is ConvertChecked to int16.
+func ToInt16Checked[T Integer](v T) (int16, bool) {
+	return ConvertChecked[int16](v)
+}
+
+// ToInt32Checked is ConvertChecked to int32.
+func ToInt32Checked[T Integer](v T) (int32, bool) {
+	return ConvertChecked[int32](v)
+}
+
+// ToInt64Checked is ConvertChecked to int64.
+func ToInt64Checked[T Integer](v T) (int64, bool) {
+	return ConvertChecked[int64](v)
+}
+
+// ToUintChecked is ConvertChecked to uint.
+func ToUintChecked[T Integer](v T) (uint, bool) {
+	return ConvertChecked[uint](v)
+}
+
+// ToUint8Checked is ConvertChecked to uint8.
+func ToUint8Checked[T Integer](v T) (uint8, bool) {
+	return ConvertChecked[uint8](v)
+}
+
+// ToUint16Checked is ConvertChecked to uint16.
+func ToUint16Checked[T Integer](v T) (uint16, bool) {
+	return ConvertChecked[uint16](v)
+}
+
+// ToUint32Checked is ConvertChecked to uint32.
+func ToUint32Checked[T Integer](v T) (uint32, bool) {
+	return ConvertChecked[uint32](v)
+}
+
+// ToUint64Checked is ConvertChecked to uint64.
+func ToUint64Checked[T Integer](v T) (uint64, bool) {
+	return ConvertChecked[uint64](v)
+}
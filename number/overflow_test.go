@@ -0,0 +1,165 @@
+package number
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func toBig[T Integer](v T) *big.Int {
+	if isSigned[T]() {
+		return big.NewInt(int64(v))
+	}
+	return new(big.Int).SetUint64(uint64(v))
+}
+
+// fromBig returns z, which must be within the bounds of T, as a T.
+func fromBig[T Integer](z *big.Int) T {
+	if z.Sign() < 0 {
+		return T(z.Int64())
+	}
+	return T(z.Uint64())
+}
+
+// clampBig returns z clamped to the bounds of T, and whether it is within
+// them.
+func clampBig[T Integer](z *big.Int) (T, bool) {
+	switch {
+	case z.Cmp(toBig(minOf[T]())) < 0:
+		return minOf[T](), false
+	case z.Cmp(toBig(maxOf[T]())) > 0:
+		return maxOf[T](), false
+	}
+	return fromBig[T](z), true
+}
+
+func checkArith[T Integer](t *testing.T, a, b T) {
+	t.Helper()
+
+	x, y := toBig(a), toBig(b)
+	ops := []struct {
+		name    string
+		checked func(a, b T) (T, bool)
+		sat     func(a, b T) T
+		wrapped T
+		exact   *big.Int
+	}{
+		{"Add", AddChecked[T], AddSat[T], a + b, new(big.Int).Add(x, y)},
+		{"Sub", SubChecked[T], SubSat[T], a - b, new(big.Int).Sub(x, y)},
+		{"Mul", MulChecked[T], MulSat[T], a * b, new(big.Int).Mul(x, y)},
+	}
+	for _, op := range ops {
+		want, wantOK := clampBig[T](op.exact)
+		if got, ok := op.checked(a, b); got != op.wrapped || ok != wantOK {
+			t.Fatalf("%sChecked[%T](%v, %v) = %v, %v, want %v, %v", op.name, a, a, b, got, ok, op.wrapped, wantOK)
+		}
+		if got := op.sat(a, b); got != want {
+			t.Fatalf("%sSat[%T](%v, %v) = %v, want %v", op.name, a, a, b, got, want)
+		}
+	}
+
+	if b == 0 {
+		if got, ok := DivChecked(a, b); got != 0 || ok {
+			t.Fatalf("DivChecked[%T](%v, 0) = %v, %v, want 0, false", a, a, got, ok)
+		}
+		want := T(0)
+		switch {
+		case a > 0:
+			want = maxOf[T]()
+		case a < 0:
+			want = minOf[T]()
+		}
+		if got := DivSat(a, b); got != want {
+			t.Fatalf("DivSat[%T](%v, 0) = %v, want %v", a, a, got, want)
+		}
+		return
+	}
+
+	want, wantOK := clampBig[T](new(big.Int).Quo(x, y))
+	wantChecked := want
+	if !wantOK {
+		wantChecked = a
+	}
+	if got, ok := DivChecked(a, b); got != wantChecked || ok != wantOK {
+		t.Fatalf("DivChecked[%T](%v, %v) = %v, %v, want %v, %v", a, a, b, got, ok, wantChecked, wantOK)
+	}
+	if got := DivSat(a, b); got != want {
+		t.Fatalf("DivSat[%T](%v, %v) = %v, want %v", a, a, b, got, want)
+	}
+}
+
+func checkConvert[To, From Integer](t *testing.T, v From) {
+	t.Helper()
+
+	want, wantOK := clampBig[To](toBig(v))
+	if got, ok := ConvertChecked[To](v); got != To(v) || ok != wantOK {
+		t.Fatalf("ConvertChecked[%T](%T(%v)) = %v, %v, want %v, %v", got, v, v, got, ok, To(v), wantOK)
+	}
+	if got := ConvertSat[To](v); got != want {
+		t.Fatalf("ConvertSat[%T](%T(%v)) = %v, want %v", got, v, v, got, want)
+	}
+}
+
+func checkConvertFrom[From Integer](t *testing.T, v From) {
+	t.Helper()
+
+	checkConvert[int](t, v)
+	checkConvert[int8](t, v)
+	checkConvert[int16](t, v)
+	checkConvert[int32](t, v)
+	checkConvert[int64](t, v)
+	checkConvert[uint](t, v)
+	checkConvert[uint8](t, v)
+	checkConvert[uint16](t, v)
+	checkConvert[uint32](t, v)
+	checkConvert[uint64](t, v)
+}
+
+var overflowEdges = []int64{
+	0, 1, -1, 2, -2,
+	math.MaxInt8, math.MinInt8, math.MaxUint8,
+	math.MaxInt16, math.MinInt16, math.MaxUint16,
+	math.MaxInt32, math.MinInt32, math.MaxUint32,
+	math.MaxInt64, math.MinInt64,
+}
+
+// The fuzz targets take int64 inputs and convert them to each integer type,
+// which truncates them, so every value of every type can be reached.
+
+func FuzzArithChecked(f *testing.F) {
+	for _, a := range overflowEdges {
+		for _, b := range overflowEdges {
+			f.Add(a, b)
+		}
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		checkArith(t, int(a), int(b))
+		checkArith(t, int8(a), int8(b))
+		checkArith(t, int16(a), int16(b))
+		checkArith(t, int32(a), int32(b))
+		checkArith(t, a, b)
+		checkArith(t, uint(a), uint(b))
+		checkArith(t, uint8(a), uint8(b))
+		checkArith(t, uint16(a), uint16(b))
+		checkArith(t, uint32(a), uint32(b))
+		checkArith(t, uint64(a), uint64(b))
+	})
+}
+
+func FuzzConvertChecked(f *testing.F) {
+	for _, v := range overflowEdges {
+		f.Add(v)
+	}
+	f.Fuzz(func(t *testing.T, v int64) {
+		checkConvertFrom(t, int(v))
+		checkConvertFrom(t, int8(v))
+		checkConvertFrom(t, int16(v))
+		checkConvertFrom(t, int32(v))
+		checkConvertFrom(t, v)
+		checkConvertFrom(t, uint(v))
+		checkConvertFrom(t, uint8(v))
+		checkConvertFrom(t, uint16(v))
+		checkConvertFrom(t, uint32(v))
+		checkConvertFrom(t, uint64(v))
+	})
+}
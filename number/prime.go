@@ -0,0 +1,318 @@
+package number
+
+import (
+	"math"
+	"sort"
+)
+
+const sieveSegmentSize = 1 << 15
+
+// PrimeSieve iterates over the primes in a range with a segmented sieve of
+// Eratosthenes. No matter how wide the range is, it only holds the primes up to
+// sqrt(hi) and a segment of 32Ki numbers in memory. Note that there are about
+// 200 million primes below 2^32, so sieving near the top of uint64 is costly
+// no matter how narrow the range is; use NextPrime there instead.
+//
+// An example:
+//     s := number.NewPrimeSieve(100, 200)
+//     for p, ok := s.Next(); ok; p, ok = s.Next() {
+//         fmt.Println(p)
+//     }
+type PrimeSieve struct {
+	hi       uint64
+	base     []uint64 // primes up to sqrt(hi), extended lazily
+	baseDone uint64   // base contains all the primes <= baseDone
+	segStart uint64
+	segment  []bool // segment[i] is true if segStart+i is composite
+	pos      int
+	done     bool
+}
+
+// NewPrimeSieve returns a PrimeSieve over the primes in [lo, hi].
+func NewPrimeSieve(lo, hi uint64) *PrimeSieve {
+	s := &PrimeSieve{
+		hi:       hi,
+		baseDone: 1,
+		segStart: Max(lo, 2),
+		segment:  make([]bool, sieveSegmentSize),
+	}
+	s.done = s.segStart > hi
+	if !s.done {
+		s.sieve()
+	}
+
+	return s
+}
+
+// sieve sieves the segment starting at segStart.
+func (s *PrimeSieve) sieve() {
+	segEnd := s.segStart + sieveSegmentSize - 1
+	if segEnd < s.segStart || segEnd > s.hi {
+		segEnd = s.hi
+	}
+	s.extendBase(Sqrt(segEnd))
+
+	for i := range s.segment {
+		s.segment[i] = false
+	}
+	for _, p := range s.base {
+		if p*p > segEnd {
+			break
+		}
+
+		// the first multiple of p in the segment, but not p itself
+		m := Max(p*p, CeilDiv(s.segStart, p)*p)
+		for ; m <= segEnd && m >= s.segStart; m += p {
+			s.segment[m-s.segStart] = true
+		}
+	}
+
+	s.pos = 0
+}
+
+// extendBase makes sure base contains all the primes <= n, with a sieve of
+// Eratosthenes over (baseDone, n], segmented as well to bound the memory.
+func (s *PrimeSieve) extendBase(n uint64) {
+	for s.baseDone < n {
+		lo := s.baseDone + 1
+		hi := Min(n, lo+sieveSegmentSize-1)
+
+		composite := make([]bool, hi-lo+1)
+		for _, p := range s.base {
+			for m := Max(p*p, CeilDiv(lo, p)*p); m <= hi; m += p {
+				composite[m-lo] = true
+			}
+		}
+		for i, c := range composite {
+			if c {
+				continue
+			}
+			p := lo + uint64(i)
+			s.base = append(s.base, p)
+			for m := p * p; m <= hi; m += p {
+				composite[m-lo] = true
+			}
+		}
+
+		s.baseDone = hi
+	}
+}
+
+// Next returns the next prime. ok is false if there is no more prime in the
+// range.
+func (s *PrimeSieve) Next() (p uint64, ok bool) {
+	for !s.done {
+		for ; s.pos < len(s.segment); s.pos++ {
+			n := s.segStart + uint64(s.pos)
+			if n > s.hi || n < s.segStart {
+				s.done = true
+				return 0, false
+			}
+			if !s.segment[s.pos] {
+				s.pos++
+				return n, true
+			}
+		}
+
+		next := s.segStart + sieveSegmentSize
+		if next < s.segStart || next > s.hi {
+			s.done = true
+			break
+		}
+		s.segStart = next
+		s.sieve()
+	}
+
+	return 0, false
+}
+
+// PrimesUpTo returns all the primes <= n in ascending order.
+func PrimesUpTo(n uint64) []uint64 {
+	var primes []uint64
+	s := NewPrimeSieve(2, n)
+	for p, ok := s.Next(); ok; p, ok = s.Next() {
+		primes = append(primes, p)
+	}
+	return primes
+}
+
+var smallPrimes = [...]uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrime reports whether n is prime. It is a deterministic Miller-Rabin test
+// with the first 12 primes as witnesses, which is known to be correct for all
+// n < 3.18*10^23, so for all uint64.
+func IsPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range smallPrimes {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	if n < 41*41 {
+		return true
+	}
+
+	// n-1 = d * 2^r with d odd
+	d, r := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		r++
+	}
+
+next:
+	for _, a := range smallPrimes {
+		x := ModPow(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		for i := 1; i < r; i++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				continue next
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// NextPrime returns the smallest prime greater than n. ok is false if there is
+// no such prime in uint64.
+func NextPrime(n uint64) (p uint64, ok bool) {
+	switch {
+	case n < 2:
+		return 2, true
+	case n >= largestPrime64:
+		return 0, false
+	}
+
+	for p = n + 1 | 1; p > n; p += 2 {
+		if IsPrime(p) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// PrevPrime returns the largest prime less than n. ok is false if n <= 2.
+func PrevPrime(n uint64) (p uint64, ok bool) {
+	switch {
+	case n <= 2:
+		return 0, false
+	case n == 3:
+		return 2, true
+	}
+
+	for p = (n - 2) | 1; ; p -= 2 {
+		if IsPrime(p) {
+			return p, true
+		}
+	}
+}
+
+// Factorize returns the prime factors of n in ascending order, with
+// multiplicity, e.g. Factorize(360) is [2 2 2 3 3 5]. It returns nil for n < 2.
+//
+// Small factors are found by trial division, and the rest by Pollard's rho
+// algorithm with Brent's cycle detection, so even the product of two 32-bit
+// primes is factorized in milliseconds.
+func Factorize(n uint64) []uint64 {
+	if n < 2 {
+		return nil
+	}
+
+	var factors []uint64
+	for _, p := range smallPrimes {
+		for n%p == 0 {
+			factors = append(factors, p)
+			n /= p
+		}
+	}
+	for p := uint64(41); p < 1000 && p*p <= n; p += 2 {
+		for n%p == 0 {
+			factors = append(factors, p)
+			n /= p
+		}
+	}
+	if n == 1 {
+		return factors
+	}
+
+	stack := []uint64{n}
+	for len(stack) > 0 {
+		m := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if IsPrime(m) {
+			factors = append(factors, m)
+			continue
+		}
+		d := pollardRho(m)
+		stack = append(stack, d, m/d)
+	}
+
+	sort.Slice(factors, func(i, j int) bool { return factors[i] < factors[j] })
+	return factors
+}
+
+// pollardRho returns a non-trivial factor of an odd composite n.
+func pollardRho(n uint64) uint64 {
+	if r := Sqrt(n); r*r == n {
+		return r
+	}
+
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 {
+			return addMod(mulMod(x, x, n), c, n)
+		}
+
+		const batch = 128
+		var x, ys uint64
+		y, q, g := uint64(2), uint64(1), uint64(1)
+		for r := 1; g == 1; r *= 2 {
+			x = y
+			for i := 0; i < r; i++ {
+				y = f(y)
+			}
+			for k := 0; k < r && g == 1; k += batch {
+				ys = y
+				for i := 0; i < Min(batch, r-k); i++ {
+					y = f(y)
+					q = mulMod(q, absDiff(x, y), n)
+				}
+				g = GCD(q, n)
+			}
+		}
+
+		// the batched product hit 0 mod n, backtrack one step at a time
+		if g == n {
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = GCD(absDiff(x, ys), n)
+			}
+		}
+		if g != n {
+			return g
+		}
+	}
+}
+
+// addMod returns a+b mod m, where a and b are less than m.
+func addMod(a, b, m uint64) uint64 {
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// largestPrime64 is the largest prime less than 2^64.
+const largestPrime64 uint64 = math.MaxUint64 - 58
@@ -0,0 +1,92 @@
+package number
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestPrimeSieve(t *testing.T) {
+	primes := PrimesUpTo(10000)
+	if len(primes) != 1229 {
+		t.Errorf("len(PrimesUpTo(10000)) = %d, want 1229", len(primes))
+	}
+
+	// across several segments, far from 0
+	lo, hi := uint64(1)<<40, uint64(1)<<40+3*sieveSegmentSize
+	s := NewPrimeSieve(lo, hi)
+	next := lo
+	for p, ok := s.Next(); ok; p, ok = s.Next() {
+		for ; next < p; next++ {
+			if IsPrime(next) {
+				t.Fatalf("NewPrimeSieve(%d, %d) skipped %d", lo, hi, next)
+			}
+		}
+		if !IsPrime(p) {
+			t.Fatalf("NewPrimeSieve(%d, %d) yielded composite %d", lo, hi, p)
+		}
+		next = p + 1
+	}
+}
+
+func FuzzIsPrime(f *testing.F) {
+	for _, n := range []uint64{0, 1, 2, 4, 561, 3215031751, 4294967291, largestPrime64, math.MaxUint64} {
+		f.Add(n)
+	}
+	f.Fuzz(func(t *testing.T, n uint64) {
+		// ProbablyPrime is exact below 2^64
+		want := new(big.Int).SetUint64(n).ProbablyPrime(0)
+		if got := IsPrime(n); got != want {
+			t.Fatalf("IsPrime(%d) = %v, want %v", n, got, want)
+		}
+	})
+}
+
+func FuzzFactorize(f *testing.F) {
+	for _, n := range []uint64{0, 1, 2, 360, 4294967291 * 4294967279, largestPrime64, math.MaxUint64} {
+		f.Add(n)
+	}
+	f.Fuzz(func(t *testing.T, n uint64) {
+		factors := Factorize(n)
+		if n < 2 {
+			if factors != nil {
+				t.Fatalf("Factorize(%d) = %v, want nil", n, factors)
+			}
+			return
+		}
+
+		prod := new(big.Int).SetUint64(1)
+		for i, p := range factors {
+			if !IsPrime(p) || i > 0 && p < factors[i-1] {
+				t.Fatalf("Factorize(%d) = %v, not ascending primes", n, factors)
+			}
+			prod.Mul(prod, new(big.Int).SetUint64(p))
+		}
+		if !prod.IsUint64() || prod.Uint64() != n {
+			t.Fatalf("Factorize(%d) = %v, whose product is %v", n, factors, prod)
+		}
+	})
+}
+
+func BenchmarkIsPrime(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsPrime(largestPrime64 - uint64(i%1000))
+	}
+}
+
+func BenchmarkFactorize(b *testing.B) {
+	// the product of the two largest 32-bit primes, the worst case of
+	// Pollard's rho
+	const n = 4294967291 * 4294967279
+	for i := 0; i < b.N; i++ {
+		Factorize(n)
+	}
+}
+
+func BenchmarkPrimeSieve(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s := NewPrimeSieve(1<<40, 1<<40+1<<20)
+		for _, ok := s.Next(); ok; _, ok = s.Next() {
+		}
+	}
+}
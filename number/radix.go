@@ -0,0 +1,232 @@
+package number
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultAlphabet is the digits used by RadixFormat when Alphabet is empty.
+// It is the same as the one of strconv for radices up to 36, and of big.Int
+// for radices up to 62.
+const DefaultAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// RadixFormat specifies how FormatRadix and ParseRadix represent integers.
+// The zero value is plain base 10.
+//
+// For example,
+//     RadixFormat{Radix: 2, Prefix: "0b", MinDigits: 8, GroupSize: 4, Separator: "_"}
+// formats 172 as "0b1010_1100", and
+//     RadixFormat{Radix: 32, Alphabet: "0123456789ABCDEFGHJKMNPQRSTVWXYZ"}
+// is Crockford's Base32.
+type RadixFormat struct {
+	// Radix is the base, between 2 and the length of Alphabet. 0 means 10.
+	Radix int
+	// Alphabet is the digits, the i-th rune for the value i. Only the first
+	// Radix runes are used, and they must be distinct. Empty means
+	// DefaultAlphabet.
+	Alphabet string
+	// Prefix goes after the sign and before the digits, e.g. "0x". It is
+	// optional when parsing.
+	Prefix string
+	// MinDigits pads the digits on the left with the zero digit to at least
+	// this length.
+	MinDigits int
+	// GroupSize, if positive, is the number of digits between separators,
+	// counted from the right.
+	GroupSize int
+	// Separator goes between groups, e.g. "_" or " ". When parsing, it is
+	// accepted between any two digits regardless of GroupSize.
+	Separator string
+}
+
+// digits returns the radix and the digits of f. It panics if f is invalid.
+func (f *RadixFormat) digits() (int, []rune) {
+	radix := f.Radix
+	if radix == 0 {
+		radix = 10
+	}
+	alphabet := f.Alphabet
+	if alphabet == "" {
+		alphabet = DefaultAlphabet
+	}
+
+	digits := []rune(alphabet)
+	if radix < 2 || radix > len(digits) {
+		panic("number: invalid radix " + strconv.Itoa(radix))
+	}
+	digits = digits[:radix]
+	seen := make(map[rune]bool, radix)
+	for _, d := range digits {
+		if seen[d] {
+			panic("number: duplicate digit " + strconv.QuoteRune(d) + " in radix alphabet")
+		}
+		seen[d] = true
+	}
+
+	return radix, digits
+}
+
+// foldCase reports whether f uses DefaultAlphabet with a radix small enough for
+// it to be case-insensitive, like strconv.
+func (f *RadixFormat) foldCase(radix int) bool {
+	return (f.Alphabet == "" || f.Alphabet == DefaultAlphabet) && radix <= 36
+}
+
+// FormatRadix formats n as specified by f. It panics if f has an invalid radix
+// or alphabet.
+func FormatRadix[T Integer](n T, f RadixFormat) string {
+	radix, digits := f.digits()
+
+	var neg bool
+	var u uint64
+	if isSigned[T]() {
+		// convert to int64 first, so that the negation of the smallest value
+		// of T does not overflow, except for int64 itself, for which the
+		// wrapped value is still right as a uint64
+		i := int64(n)
+		neg = i < 0
+		u = uint64(i)
+		if neg {
+			u = uint64(-i)
+		}
+	} else {
+		u = uint64(n)
+	}
+
+	var buf [64]rune
+	i := len(buf)
+	for {
+		i--
+		buf[i] = digits[u%uint64(radix)]
+		u /= uint64(radix)
+		if u == 0 {
+			break
+		}
+	}
+
+	return f.layout(neg, buf[i:], digits[0])
+}
+
+// FormatRadixBig is like FormatRadix, but for a big.Int.
+func FormatRadixBig(n *big.Int, f RadixFormat) string {
+	radix, digits := f.digits()
+
+	// big.Int.Text uses DefaultAlphabet, map it to f.Alphabet
+	text := new(big.Int).Abs(n).Text(radix)
+	buf := make([]rune, len(text))
+	for i := 0; i < len(text); i++ {
+		buf[i] = digits[strings.IndexByte(DefaultAlphabet, text[i])]
+	}
+
+	return f.layout(n.Sign() < 0, buf, digits[0])
+}
+
+// layout pads and groups the digits, and adds the sign and the prefix.
+func (f *RadixFormat) layout(neg bool, digits []rune, zero rune) string {
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	b.WriteString(f.Prefix)
+
+	n := Max(len(digits), f.MinDigits)
+	for i := 0; i < n; i++ {
+		if i > 0 && f.GroupSize > 0 && (n-i)%f.GroupSize == 0 {
+			b.WriteString(f.Separator)
+		}
+		if pad := n - len(digits); i < pad {
+			b.WriteRune(zero)
+		} else {
+			b.WriteRune(digits[i-pad])
+		}
+	}
+
+	return b.String()
+}
+
+// ParseRadix parses s as specified by f, with an optional sign. The prefix is
+// optional, and separators are allowed between digits. Digits are
+// case-insensitive if f uses DefaultAlphabet and a radix up to 36, like
+// strconv.ParseInt. Any padding is accepted.
+//
+// If s is not a valid integer, the error wraps strconv.ErrSyntax; if the value
+// does not fit in T, it wraps strconv.ErrRange. ParseRadix panics if f has an
+// invalid radix or alphabet.
+func ParseRadix[T Integer](s string, f RadixFormat) (T, error) {
+	z, err := ParseRadixBig(s, f)
+	if err != nil {
+		return 0, err
+	}
+
+	var v T
+	var ok bool
+	switch {
+	case z.IsInt64():
+		v, ok = ConvertChecked[T](z.Int64())
+	case z.IsUint64():
+		v, ok = ConvertChecked[T](z.Uint64())
+	}
+	if !ok {
+		return 0, fmt.Errorf("parsing %q: %w", s, strconv.ErrRange)
+	}
+	return v, nil
+}
+
+// ParseRadixBig is like ParseRadix, but returns a big.Int, so it never fails
+// with strconv.ErrRange.
+func ParseRadixBig(s string, f RadixFormat) (*big.Int, error) {
+	radix, digits := f.digits()
+	fold := f.foldCase(radix)
+	values := make(map[rune]int, radix)
+	for i, d := range digits {
+		values[d] = i
+	}
+
+	rest := s
+	neg := false
+	if rest != "" && (rest[0] == '+' || rest[0] == '-') {
+		neg = rest[0] == '-'
+		rest = rest[1:]
+	}
+	if p := f.Prefix; p != "" && len(rest) >= len(p) &&
+		(rest[:len(p)] == p || fold && strings.EqualFold(rest[:len(p)], p)) {
+		rest = rest[len(p):]
+	}
+
+	// translate the digits to DefaultAlphabet for big.Int.SetString
+	var canon strings.Builder
+	for rest != "" {
+		if sep := f.Separator; sep != "" && strings.HasPrefix(rest, sep) {
+			// only between two digits
+			rest = rest[len(sep):]
+			if canon.Len() == 0 || rest == "" || strings.HasPrefix(rest, sep) {
+				return nil, fmt.Errorf("parsing %q: %w", s, strconv.ErrSyntax)
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		rest = rest[size:]
+		v, ok := values[r]
+		if !ok && fold {
+			v, ok = values[unicode.ToLower(r)]
+		}
+		if !ok {
+			return nil, fmt.Errorf("parsing %q: %w", s, strconv.ErrSyntax)
+		}
+		canon.WriteByte(DefaultAlphabet[v])
+	}
+	if canon.Len() == 0 {
+		return nil, fmt.Errorf("parsing %q: %w", s, strconv.ErrSyntax)
+	}
+
+	z, _ := new(big.Int).SetString(canon.String(), radix)
+	if neg {
+		z.Neg(z)
+	}
+	return z, nil
+}
@@ -0,0 +1,178 @@
+package number
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RangeError is the error returned by ParseRanges. It records where in the
+// expression the error is, so that it can be pointed out to the user.
+type RangeError struct {
+	Expr string // the whole expression
+	Pos  int    // byte offset in Expr
+	Msg  string
+}
+
+// Error returns the error in the form of
+//     parsing range "1-5,x": column 5: expected a number
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("parsing range %q: column %d: %s", e.Expr, e.column(), e.Msg)
+}
+
+// Caret returns the expression and a caret under the error position on the
+// next line, followed by the message, e.g.
+//     1-5,x
+//         ^ expected a number
+// which is suitable for printing to stderr below Error.
+func (e *RangeError) Caret() string {
+	return e.Expr + "\n" + strings.Repeat(" ", e.column()-1) + "^ " + e.Msg
+}
+
+// column returns the 1-based column of Pos in runes.
+func (e *RangeError) column() int {
+	return utf8.RuneCountInString(e.Expr[:e.Pos]) + 1
+}
+
+// ParseRanges parses a selector of lines, pages, columns, etc. like the ones
+// of cut(1), such as "1-5,8,10-", into the set of 1-based indices it selects,
+// e.g. [1, 6), [8, 9) and [10, max+1).
+//
+// An expression is a comma-separated list of items, each of which is either an
+// index N, a range N-M with both ends inclusive, or an open-ended range N-
+// that selects N and everything after it. An index can be negative to count
+// from the end, -1 being max, so "-3-" selects the last 3 and "2--2" all but
+// the first and the last. Note that unlike cut(1), "-N" is therefore an index
+// rather than a range from 1. Spaces around items and numbers are allowed.
+//
+// max is the largest valid index. Indices larger than max are rejected, and
+// open-ended ranges end at max. If max is not positive, it is unknown: there
+// is no upper bound, open-ended ranges extend to math.MaxInt (exclusive), and
+// negative indices are rejected. In any case, the index math.MaxInt itself is
+// rejected, since the end of its interval would overflow.
+//
+// The error, if any, is always a *RangeError.
+func ParseRanges(expr string, max int) (*IntervalSet[int], error) {
+	p := rangeParser{expr: expr, max: max}
+	set := new(IntervalSet[int])
+
+	p.skipSpaces()
+	if p.pos == len(expr) {
+		return nil, p.errorf(p.pos, "empty expression")
+	}
+	for {
+		iv, single, err := p.item()
+		if err != nil {
+			return nil, err
+		}
+		set.Add(iv)
+
+		p.skipSpaces()
+		if p.pos == len(expr) {
+			return set, nil
+		}
+		if expr[p.pos] != ',' {
+			r, _ := utf8.DecodeRuneInString(expr[p.pos:])
+			if single {
+				return nil, p.errorf(p.pos, "unexpected %q, expected ',' or '-'", r)
+			}
+			return nil, p.errorf(p.pos, "unexpected %q, expected ','", r)
+		}
+		p.pos++
+	}
+}
+
+type rangeParser struct {
+	expr string
+	pos  int
+	max  int
+}
+
+func (p *rangeParser) errorf(pos int, format string, a ...interface{}) *RangeError {
+	return &RangeError{Expr: p.expr, Pos: pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *rangeParser) skipSpaces() {
+	for p.pos < len(p.expr) && p.expr[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// item parses an index, N-M or N-, and returns it as a half-open interval.
+// single reports whether it is a single index, which a '-' could still follow.
+func (p *rangeParser) item() (iv Interval[int], single bool, err error) {
+	p.skipSpaces()
+	start := p.pos
+	lo, err := p.index()
+	if err != nil {
+		return Interval[int]{}, false, err
+	}
+
+	p.skipSpaces()
+	if p.pos == len(p.expr) || p.expr[p.pos] != '-' {
+		return Interval[int]{lo, lo + 1}, true, nil
+	}
+	p.pos++
+
+	p.skipSpaces()
+	if p.pos == len(p.expr) || p.expr[p.pos] == ',' {
+		// index rejects math.MaxInt, so it is the end even if it is max
+		if p.max <= 0 || p.max == math.MaxInt {
+			return Interval[int]{lo, math.MaxInt}, false, nil
+		}
+		return Interval[int]{lo, p.max + 1}, false, nil
+	}
+
+	hi, err := p.index()
+	if err != nil {
+		return Interval[int]{}, false, err
+	}
+	if hi < lo {
+		text := strings.TrimSpace(p.expr[start:p.pos])
+		return Interval[int]{}, false, p.errorf(start, "reversed range %s", text)
+	}
+	return Interval[int]{lo, hi + 1}, false, nil
+}
+
+// index parses an optionally negative index, and resolves it to a positive
+// one. math.MaxInt is rejected, since the exclusive end of its interval would
+// overflow.
+func (p *rangeParser) index() (int, error) {
+	start := p.pos
+	if p.pos < len(p.expr) && p.expr[p.pos] == '-' {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == digits {
+		return 0, p.errorf(digits, "expected a number")
+	}
+
+	s := p.expr[start:p.pos]
+	n, err := strconv.Atoi(s)
+	switch {
+	case err != nil:
+		return 0, p.errorf(start, "index %s out of range", s)
+	case n == 0:
+		return 0, p.errorf(start, "index 0 is invalid, indices start at 1")
+	case n < 0 && p.max <= 0:
+		return 0, p.errorf(start, "negative index %d without a known maximum", n)
+	case n < 0:
+		if n < -p.max {
+			return 0, p.errorf(start, "index %d out of range, there are only %d", n, p.max)
+		}
+		// p.max + 1 + n, without overflowing if p.max is math.MaxInt
+		n = p.max - (-n - 1)
+	case p.max > 0 && n > p.max:
+		return 0, p.errorf(start, "index %d out of range, there are only %d", n, p.max)
+	}
+	if n == math.MaxInt {
+		return 0, p.errorf(start, "index %s out of range", s)
+	}
+
+	return n, nil
+}
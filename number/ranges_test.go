@@ -0,0 +1,75 @@
+package number
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestParseRanges(t *testing.T) {
+	maxInt := strconv.Itoa(math.MaxInt)
+	tests := []struct {
+		expr string
+		max  int
+		want string
+	}{
+		{"1-5,8,10-", 20, "{[1, 6), [8, 9), [10, 21)}"},
+		{" 2 - 4 , 3 ", 0, "{[2, 5)}"},
+		{"-3-", 10, "{[8, 11)}"},
+		{"2--2", 10, "{[2, 10)}"},
+		{"7-", 0, "{[7, " + maxInt + ")}"},
+		{"-1", math.MaxInt, "error"},
+		{"-2", math.MaxInt, "{[" + strconv.Itoa(math.MaxInt-1) + ", " + maxInt + ")}"},
+		{"1-", math.MaxInt, "{[1, " + maxInt + ")}"},
+		{maxInt, 0, "error"},
+		{"1-" + maxInt, 0, "error"},
+		{maxInt + "-", 0, "error"},
+		{strconv.Itoa(math.MaxInt - 1), 0, "{[" + strconv.Itoa(math.MaxInt-1) + ", " + maxInt + ")}"},
+		{"0", 0, "error"},
+		{"5-3", 0, "error"},
+		{"-1", 0, "error"},
+		{"11", 10, "error"},
+		{"", 0, "error"},
+	}
+
+	for _, tt := range tests {
+		set, err := ParseRanges(tt.expr, tt.max)
+		got := "error"
+		if err == nil {
+			got = set.String()
+		} else if !errors.As(err, new(*RangeError)) {
+			t.Errorf("ParseRanges(%q, %d) error %v is not a *RangeError", tt.expr, tt.max, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseRanges(%q, %d) = %s (%v), want %s", tt.expr, tt.max, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseRangesError(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"1-5,x", 4, "expected a number"},
+		{"1 2", 2, `unexpected '2', expected ',' or '-'`},
+		{"1-5 6", 4, `unexpected '6', expected ','`},
+		{"1-5-6", 3, `unexpected '-', expected ','`},
+		{"3-2", 0, "reversed range 3-2"},
+		{"1," + strconv.Itoa(math.MaxInt), 2, "index " + strconv.Itoa(math.MaxInt) + " out of range"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseRanges(tt.expr, 0)
+		var re *RangeError
+		if !errors.As(err, &re) {
+			t.Errorf("ParseRanges(%q) error = %v, want a *RangeError", tt.expr, err)
+			continue
+		}
+		if re.Pos != tt.pos || re.Msg != tt.msg {
+			t.Errorf("ParseRanges(%q) error at %d: %s, want at %d: %s", tt.expr, re.Pos, re.Msg, tt.pos, tt.msg)
+		}
+	}
+}
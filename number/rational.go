@@ -0,0 +1,192 @@
+package number
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+// ContinuedFraction returns the terms [a0; a1, a2, ...] of the regular
+// continued fraction of r, i.e. r = a0 + 1/(a1 + 1/(a2 + ...)), where a0 is
+// floor(r) and the other terms are positive. Since r is rational, the expansion
+// is finite, e.g. 415/93 is [4; 2, 6, 7]. If maxTerms is positive, at most that
+// many terms are returned.
+func ContinuedFraction(r *big.Rat, maxTerms int) []*big.Int {
+	p := new(big.Int).Set(r.Num())
+	q := new(big.Int).Set(r.Denom())
+
+	var terms []*big.Int
+	for q.Sign() != 0 && (maxTerms <= 0 || len(terms) < maxTerms) {
+		// Euclidean division, so that the remainder is non-negative and a0
+		// is the floor even for a negative r
+		a, m := new(big.Int).DivMod(p, q, new(big.Int))
+		terms = append(terms, a)
+		p, q = q, m
+	}
+	return terms
+}
+
+// BestRational returns the best rational approximation of v whose denominator
+// is at most maxDen, i.e. the fraction closest to v among all such fractions,
+// with the smallest denominator if there is a tie. For example, with maxDen 10,
+// 1.7777 is 16/9 and math.Pi is 22/7, and with maxDen 1000, math.Pi is
+// 355/113.
+//
+// It returns nil if v is NaN or infinite, and panics if maxDen < 1.
+func BestRational(v float64, maxDen int64) *big.Rat {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return BestRationalRat(new(big.Rat).SetFloat64(v), maxDen)
+}
+
+// BestRationalRat is like BestRational, but approximates a big.Rat.
+func BestRationalRat(r *big.Rat, maxDen int64) *big.Rat {
+	if maxDen < 1 {
+		panic("number: BestRational with non-positive maximum denominator")
+	}
+
+	bound := big.NewInt(maxDen)
+	if r.Denom().Cmp(bound) <= 0 {
+		return new(big.Rat).Set(r)
+	}
+
+	// convergents h/k of the continued fraction, with h0/k0 the one before
+	// the last h1/k1
+	h0, k0 := big.NewInt(0), big.NewInt(1)
+	h1, k1 := big.NewInt(1), big.NewInt(0)
+	for _, a := range ContinuedFraction(r, 0) {
+		k := new(big.Int).Mul(a, k1)
+		k.Add(k, k0)
+		if k.Cmp(bound) > 0 {
+			// the best approximation is either the last convergent, or the
+			// semiconvergent with the largest denominator within the bound
+			t := new(big.Int).Sub(bound, k0)
+			t.Quo(t, k1)
+			semi := new(big.Rat).SetFrac(
+				new(big.Int).Add(new(big.Int).Mul(t, h1), h0),
+				new(big.Int).Add(new(big.Int).Mul(t, k1), k0),
+			)
+			conv := new(big.Rat).SetFrac(h1, k1)
+
+			dSemi := new(big.Rat).Sub(semi, r)
+			dConv := new(big.Rat).Sub(conv, r)
+			if dSemi.Abs(dSemi).Cmp(dConv.Abs(dConv)) < 0 {
+				return semi
+			}
+			return conv
+		}
+
+		h := new(big.Int).Mul(a, h1)
+		h.Add(h, h0)
+		h0, k0, h1, k1 = h1, k1, h, k
+	}
+
+	// unreachable, since the last convergent is r itself, whose denominator
+	// exceeds the bound
+	return new(big.Rat).SetFrac(h1, k1)
+}
+
+// FormatMixed formats r as a mixed fraction, i.e. an integer followed by a
+// proper fraction, such as "1 1/2", "-2 3/4", "3/4" or "5".
+func FormatMixed(r *big.Rat) string {
+	n, d := r.Num(), r.Denom()
+	i, m := new(big.Int).QuoRem(n, d, new(big.Int))
+
+	var b strings.Builder
+	if n.Sign() < 0 {
+		b.WriteByte('-')
+		m.Neg(m)
+		i.Neg(i)
+	}
+	if i.Sign() != 0 || m.Sign() == 0 {
+		b.WriteString(i.String())
+	}
+	if m.Sign() != 0 {
+		if i.Sign() != 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(m.String())
+		b.WriteByte('/')
+		b.WriteString(d.String())
+	}
+
+	return b.String()
+}
+
+// FormatRepeating formats r in decimal with the repeating part, if any, in
+// parentheses, e.g. 1/6 is "0.1(6)", 1/3 is "0.(3)", -22/7 is "-3.(142857)" and
+// 1/8 is "0.125".
+//
+// The period of a fraction can be as long as its denominator, so if maxDigits
+// is positive and the fractional part would need more than maxDigits digits,
+// r is instead rounded half to even to maxDigits decimal places and followed
+// by "...", e.g. 1/97 with maxDigits 10 is "0.0103092784...".
+func FormatRepeating(r *big.Rat, maxDigits int) string {
+	n := new(big.Int).Abs(r.Num())
+	d := r.Denom()
+	sign := ""
+	if r.Sign() < 0 {
+		sign = "-"
+	}
+
+	// the digits before the period are as many as the larger power of 2 or
+	// 5 in the denominator
+	pre := 0
+	for _, p := range []int64{2, 5} {
+		e, q, m := 0, new(big.Int).Set(d), new(big.Int)
+		bp := big.NewInt(p)
+		for {
+			q.QuoRem(q, bp, m)
+			if m.Sign() != 0 {
+				break
+			}
+			e++
+		}
+		pre = Max(pre, e)
+	}
+
+	ten := big.NewInt(10)
+	i, rem := new(big.Int).QuoRem(n, d, new(big.Int))
+	var frac strings.Builder
+	digit := new(big.Int)
+	next := func() {
+		rem.Mul(rem, ten)
+		digit.QuoRem(rem, d, rem)
+		frac.WriteByte(byte('0' + digit.Int64()))
+	}
+	tooLong := func() string {
+		scaled := new(big.Int).Mul(r.Num(), pow10(maxDigits))
+		q := roundQuo(scaled, d, RoundHalfEven)
+		return NewDecimalFromBigInt(q, -maxDigits).String() + "..."
+	}
+
+	for k := 0; k < pre && rem.Sign() != 0; k++ {
+		if maxDigits > 0 && frac.Len() == maxDigits {
+			return tooLong()
+		}
+		next()
+	}
+	if rem.Sign() == 0 {
+		if frac.Len() == 0 {
+			return sign + i.String()
+		}
+		return sign + i.String() + "." + frac.String()
+	}
+
+	// the remainders repeat from here
+	start := new(big.Int).Set(rem)
+	preLen := frac.Len()
+	for {
+		if maxDigits > 0 && frac.Len() == maxDigits {
+			return tooLong()
+		}
+		next()
+		if rem.Cmp(start) == 0 {
+			break
+		}
+	}
+
+	s := frac.String()
+	return sign + i.String() + "." + s[:preLen] + "(" + s[preLen:] + ")"
+}
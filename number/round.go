@@ -2,32 +2,209 @@ package number
 
 import (
 	"math"
+	"math/big"
+	"strconv"
+	"strings"
 )
 
-// From github.com/gonum/gonum/floats.Round
+// RoundMode specifies how a value is rounded when it cannot be represented
+// exactly at the requested precision.
+type RoundMode int
+
+const (
+	// RoundHalfEven rounds to the nearest neighbor, and ties to the even one.
+	// It is also known as banker's rounding.
+	RoundHalfEven RoundMode = iota
+	// RoundHalfUp rounds to the nearest neighbor, and ties away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest neighbor, and ties toward zero.
+	RoundHalfDown
+	// RoundTowardZero truncates.
+	RoundTowardZero
+	// RoundAwayFromZero rounds to the neighbor farther from zero.
+	RoundAwayFromZero
+	// RoundCeiling rounds toward +Inf.
+	RoundCeiling
+	// RoundFloor rounds toward -Inf.
+	RoundFloor
+)
+
+func (m RoundMode) String() string {
+	switch m {
+	case RoundHalfEven:
+		return "HalfEven"
+	case RoundHalfUp:
+		return "HalfUp"
+	case RoundHalfDown:
+		return "HalfDown"
+	case RoundTowardZero:
+		return "TowardZero"
+	case RoundAwayFromZero:
+		return "AwayFromZero"
+	case RoundCeiling:
+		return "Ceiling"
+	case RoundFloor:
+		return "Floor"
+	}
+
+	return "RoundMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Round returns v rounded to prec decimal places, with ties away from zero.
+// A negative prec rounds to the left of the decimal point, e.g. prec -2 rounds
+// to hundreds.
+//
+// It is a short cut for RoundWith(v, prec, RoundHalfUp).
 func Round(v float64, prec int) float64 {
-	if v == 0 {
-		return 0
+	return RoundWith(v, prec, RoundHalfUp)
+}
+
+// maxFloatPlaces bounds the number of decimal places of the shortest decimal
+// representation of a float64, which has at most 17 significant digits and an
+// exponent of at least -324.
+const maxFloatPlaces = 340
+
+// RoundWith returns v rounded to prec decimal places using mode.
+//
+// The rounding is done on the shortest decimal representation of v, i.e. the
+// one printed by strconv.FormatFloat(v, 'g', -1, 64), so RoundWith(1.005, 2,
+// RoundHalfUp) is 1.01 rather than 1, even though the nearest float64 of 1.005
+// is slightly smaller than it. NaN and ±Inf are returned as is.
+//
+// A float64 has at most maxFloatPlaces decimal places, so v is returned as is
+// for a larger prec, and a prec below -maxFloatPlaces is the same as
+// -maxFloatPlaces, i.e. the result is 0 or, if mode rounds v away from zero,
+// ±Inf.
+func RoundWith(v float64, prec int, mode RoundMode) float64 {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	if prec > maxFloatPlaces || prec >= 0 && v == math.Trunc(v) {
+		return v
+	}
+	if prec < -maxFloatPlaces {
+		prec = -maxFloatPlaces
 	}
 
-	if prec >= 0 && v == math.Trunc(v) {
+	scale := new(big.Rat).SetInt(pow10(Abs(prec)))
+	if prec < 0 {
+		scale.Inv(scale)
+	}
+
+	r := exactDecimal(v)
+	r.Mul(r, scale)
+	r.SetInt(roundQuo(r.Num(), r.Denom(), mode))
+	r.Quo(r, scale)
+
+	return ratToFloat(r)
+}
+
+// RoundSig returns v rounded to sig significant figures using mode. If sig is
+// less than 1 or more than 17, the most a float64 has, v is returned as is.
+//
+// Like RoundWith, the rounding is done on the shortest decimal representation
+// of v.
+func RoundSig(v float64, sig int, mode RoundMode) float64 {
+	// a float64 has at most 17 significant digits
+	if sig < 1 || sig > 17 || v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
 		return v
 	}
 
-	pow := math.Pow10(prec)
-	intermed := v * pow
-	if math.IsInf(intermed, 0) {
+	return RoundWith(v, sig-1-decimalExponent(v), mode)
+}
+
+// RoundToMultiple returns v rounded to a multiple of step using mode, e.g.
+// RoundToMultiple(7.3, 0.25, RoundHalfEven) is 7.25. The sign of step is
+// ignored. If step is 0, v is returned as is; if step is NaN or ±Inf, the
+// result is NaN.
+//
+// Both v and step are taken at their shortest decimal representations, so
+// RoundToMultiple(0.3, 0.1, RoundFloor) is 0.3, not 0.2.
+func RoundToMultiple(v, step float64, mode RoundMode) float64 {
+	if math.IsNaN(step) || math.IsInf(step, 0) {
+		return math.NaN()
+	}
+	if step == 0 || v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
 		return v
 	}
-	if v < 0 {
-		v = math.Ceil(intermed - 0.5)
-	} else {
-		v = math.Floor(intermed + 0.5)
+
+	s := exactDecimal(math.Abs(step))
+	r := exactDecimal(v)
+	r.Quo(r, s)
+	r.SetInt(roundQuo(r.Num(), r.Denom(), mode))
+	r.Mul(r, s)
+
+	return ratToFloat(r)
+}
+
+// roundQuo returns n/d rounded to an integer using mode. d must be positive.
+//
+// This is the single place where the rounding modes are implemented; every
+// other rounding function in this package is built on top of it.
+func roundQuo(n, d *big.Int, mode RoundMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	sign := n.Sign()
+	away := false
+	switch mode {
+	case RoundTowardZero:
+	case RoundAwayFromZero:
+		away = true
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	default:
+		// compare the remainder with the half of d
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		switch c := half.Cmp(d); {
+		case c > 0:
+			away = true
+		case c == 0:
+			switch mode {
+			case RoundHalfUp:
+				away = true
+			case RoundHalfEven:
+				away = q.Bit(0) == 1
+			}
+		}
 	}
 
-	if v == 0 {
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+
+	return q
+}
+
+// exactDecimal returns the shortest decimal representation of a finite v as a
+// *big.Rat.
+func exactDecimal(v float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	return r
+}
+
+// decimalExponent returns the exponent of the shortest decimal representation
+// of a finite, non-zero v in scientific notation.
+func decimalExponent(v float64) int {
+	s := strconv.FormatFloat(v, 'e', -1, 64)
+	e, _ := strconv.Atoi(s[strings.IndexByte(s, 'e')+1:])
+	return e
+}
+
+func ratToFloat(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	if f == 0 {
+		// avoid -0
 		return 0
 	}
+	return f
+}
 
-	return v / pow
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
 }
@@ -0,0 +1,274 @@
+package number
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"math/rand"
+)
+
+var ErrInvalidWeights = errors.New("invalid weights")
+
+// The functions and types below take a *rand.Rand as the source of
+// randomness. If it is nil, the global source of math/rand is used; pass
+// rand.New(rand.NewSource(seed)) instead for reproducible results, e.g. in
+// tests.
+
+func randFloat64(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.Float64()
+	}
+	return rng.Float64()
+}
+
+func randInt63n(rng *rand.Rand, n int64) int64 {
+	if rng == nil {
+		return rand.Int63n(n)
+	}
+	return rng.Int63n(n)
+}
+
+// PartialShuffle shuffles a randomly such that a[:k] is a uniform random
+// sample of k elements of a in random order, and returns a[:k]. It runs only
+// the first k steps of the Fisher-Yates shuffle, so it is O(k) rather than
+// O(len(a)). It panics if k is out of [0, len(a)].
+func PartialShuffle[T any](a []T, k int, rng *rand.Rand) []T {
+	if k < 0 || k > len(a) {
+		panic("number: PartialShuffle with k out of range")
+	}
+
+	for i := 0; i < k; i++ {
+		j := i + int(randInt63n(rng, int64(len(a)-i)))
+		a[i], a[j] = a[j], a[i]
+	}
+	return a[:k]
+}
+
+// Reservoir samples k elements uniformly from a stream of unknown length
+// without holding the stream in memory, with Vitter's Algorithm R. After n
+// elements have been added, every one of them is in the sample with
+// probability k/n.
+//
+// A Reservoir is not safe for concurrent use.
+type Reservoir[T any] struct {
+	sample []T
+	k      int
+	n      int
+	rng    *rand.Rand
+}
+
+// NewReservoir returns a Reservoir of k elements. It panics if k is negative.
+func NewReservoir[T any](k int, rng *rand.Rand) *Reservoir[T] {
+	if k < 0 {
+		panic("number: negative reservoir size")
+	}
+	return &Reservoir[T]{sample: make([]T, 0, k), k: k, rng: rng}
+}
+
+// Add adds an element v from the stream.
+func (r *Reservoir[T]) Add(v T) {
+	r.n++
+	if len(r.sample) < r.k {
+		r.sample = append(r.sample, v)
+		return
+	}
+	if j := randInt63n(r.rng, int64(r.n)); j < int64(r.k) {
+		r.sample[j] = v
+	}
+}
+
+// Count returns the number of elements added.
+func (r *Reservoir[T]) Count() int {
+	return r.n
+}
+
+// Sample returns a copy of the sampled elements, which are all the elements
+// if fewer than k have been added. They are in no particular order.
+func (r *Reservoir[T]) Sample() []T {
+	s := make([]T, len(r.sample))
+	copy(s, r.sample)
+	return s
+}
+
+// Reset removes all the elements.
+func (r *Reservoir[T]) Reset() {
+	r.sample = r.sample[:0]
+	r.n = 0
+}
+
+// WeightedReservoir samples k elements without replacement from a stream of
+// weighted elements, such that an element is more likely to be sampled the
+// larger its weight is, with the A-Res algorithm of Efraimidis and Spirakis.
+// Each element gets the key u^(1/w) for a uniform random u, and the k
+// elements with the largest keys are kept in a heap, so each Add is
+// O(log k).
+//
+// A WeightedReservoir is not safe for concurrent use.
+type WeightedReservoir[T any] struct {
+	h   weightedHeap[T]
+	k   int
+	n   int
+	rng *rand.Rand
+}
+
+type weightedItem[T any] struct {
+	v   T
+	key float64 // log(u)/w, which orders the same as u^(1/w) without underflow
+}
+
+// weightedHeap is a min-heap by key.
+type weightedHeap[T any] []weightedItem[T]
+
+func (h weightedHeap[T]) Len() int           { return len(h) }
+func (h weightedHeap[T]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h weightedHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *weightedHeap[T]) Push(x interface{}) {
+	*h = append(*h, x.(weightedItem[T]))
+}
+
+func (h *weightedHeap[T]) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// NewWeightedReservoir returns a WeightedReservoir of k elements. It panics if
+// k is negative.
+func NewWeightedReservoir[T any](k int, rng *rand.Rand) *WeightedReservoir[T] {
+	if k < 0 {
+		panic("number: negative reservoir size")
+	}
+	return &WeightedReservoir[T]{h: make(weightedHeap[T], 0, k), k: k, rng: rng}
+}
+
+// Add adds an element v with weight w from the stream. Elements with a weight
+// that is not positive or finite are never sampled, but are still counted.
+func (r *WeightedReservoir[T]) Add(v T, w float64) {
+	r.n++
+	if !(w > 0) || math.IsInf(w, 0) || r.k == 0 {
+		return
+	}
+
+	key := math.Log(randFloat64(r.rng)) / w
+	switch {
+	case len(r.h) < r.k:
+		heap.Push(&r.h, weightedItem[T]{v, key})
+	case key > r.h[0].key:
+		r.h[0] = weightedItem[T]{v, key}
+		heap.Fix(&r.h, 0)
+	}
+}
+
+// Count returns the number of elements added.
+func (r *WeightedReservoir[T]) Count() int {
+	return r.n
+}
+
+// Sample returns a copy of the sampled elements, in no particular order.
+func (r *WeightedReservoir[T]) Sample() []T {
+	s := make([]T, len(r.h))
+	for i, it := range r.h {
+		s[i] = it.v
+	}
+	return s
+}
+
+// Reset removes all the elements.
+func (r *WeightedReservoir[T]) Reset() {
+	r.h = r.h[:0]
+	r.n = 0
+}
+
+// AliasSampler picks indices at random with probabilities proportional to
+// fixed weights, with Vose's alias method. Building it is O(n), and each
+// sample is O(1) regardless of the number of weights.
+//
+// An AliasSampler is safe for concurrent use, as long as each goroutine passes
+// its own *rand.Rand, or nil for the global source of math/rand, to Sample.
+type AliasSampler struct {
+	prob  []float64
+	alias []int
+}
+
+// NewAliasSampler returns an AliasSampler for weights, so that index i is
+// picked with probability weights[i]/Sum(weights). It returns
+// ErrInvalidWeights if weights is empty, has a negative, NaN or infinite
+// element, or sums to 0 or more than math.MaxFloat64.
+func NewAliasSampler(weights []float64) (*AliasSampler, error) {
+	n := len(weights)
+	if n == 0 {
+		return nil, ErrInvalidWeights
+	}
+	var total neumaier
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 0) {
+			return nil, ErrInvalidWeights
+		}
+		total.add(w)
+	}
+	sum := total.value()
+	if !(sum > 0) || math.IsInf(sum, 0) {
+		return nil, ErrInvalidWeights
+	}
+
+	s := &AliasSampler{prob: make([]float64, n), alias: make([]int, n)}
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w / sum * float64(n)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		l, g := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+
+		s.prob[l] = scaled[l]
+		s.alias[l] = g
+		scaled[g] -= 1 - scaled[l]
+		if scaled[g] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, g)
+		}
+	}
+	// what remains is 1 up to rounding errors, which can also leave some in
+	// small once large is empty. An index whose weight is 0 must never be
+	// picked though, so in case rounding leaves one there, it is aliased to an
+	// index with a positive weight instead.
+	for _, i := range large {
+		s.prob[i] = 1
+	}
+	positive := 0
+	for weights[positive] == 0 {
+		positive++
+	}
+	for _, i := range small {
+		if weights[i] > 0 {
+			s.prob[i] = 1
+		} else {
+			s.prob[i], s.alias[i] = 0, positive
+		}
+	}
+
+	return s, nil
+}
+
+// Len returns the number of weights.
+func (s *AliasSampler) Len() int {
+	return len(s.prob)
+}
+
+// Sample returns a random index.
+func (s *AliasSampler) Sample(rng *rand.Rand) int {
+	i := int(randInt63n(rng, int64(len(s.prob))))
+	if randFloat64(rng) < s.prob[i] {
+		return i
+	}
+	return s.alias[i]
+}
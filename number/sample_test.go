@@ -0,0 +1,78 @@
+package number
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAliasSamplerZeroWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	inputs := [][]float64{
+		{0, 1},
+		{1, 0},
+		{0, 0, 0, 1, 0},
+		{0, 0.1, 0.1, 0.1, 0, 0.7},
+		{0, 1.0 / 3, 1.0 / 3, 1.0 / 3, 0},
+		{1e-300, 0, 1e300, 0},
+	}
+	for i := 0; i < 200; i++ {
+		w := make([]float64, 1+rng.Intn(50))
+		for j := range w {
+			if rng.Intn(3) > 0 {
+				w[j] = rng.Float64() * math.Pow(10, float64(rng.Intn(21)-10))
+			}
+		}
+		inputs = append(inputs, w)
+	}
+
+	for _, w := range inputs {
+		s, err := NewAliasSampler(w)
+		if err != nil {
+			continue
+		}
+
+		// a zero weight is never its own pick, nor anything's alias
+		for i := range w {
+			if w[i] == 0 && s.prob[i] != 0 {
+				t.Fatalf("NewAliasSampler(%v): prob[%d] = %v, want 0", w, i, s.prob[i])
+			}
+			if s.prob[i] < 1 && w[s.alias[i]] == 0 {
+				t.Fatalf("NewAliasSampler(%v): alias[%d] = %d, whose weight is 0", w, i, s.alias[i])
+			}
+		}
+		for k := 0; k < 1000; k++ {
+			if i := s.Sample(rng); w[i] == 0 {
+				t.Fatalf("NewAliasSampler(%v) sampled %d, whose weight is 0", w, i)
+			}
+		}
+	}
+}
+
+func TestAliasSamplerDistribution(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	w := []float64{1, 0, 2, 3, 4}
+	s, err := NewAliasSampler(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 100000
+	counts := make([]int, len(w))
+	for i := 0; i < n; i++ {
+		counts[s.Sample(rng)]++
+	}
+	for i, c := range counts {
+		want := w[i] / 10 * n
+		// about 4 standard deviations
+		if math.Abs(float64(c)-want) > 4*math.Sqrt(want)+1 {
+			t.Errorf("index %d sampled %d times out of %d, want about %v", i, c, n, want)
+		}
+	}
+
+	for _, w := range [][]float64{nil, {0, 0}, {1, -1}, {math.NaN()}, {math.Inf(1)}, {math.MaxFloat64, math.MaxFloat64}} {
+		if _, err := NewAliasSampler(w); err != ErrInvalidWeights {
+			t.Errorf("NewAliasSampler(%v) error = %v, want %v", w, err, ErrInvalidWeights)
+		}
+	}
+}
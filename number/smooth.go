@@ -0,0 +1,281 @@
+package number
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SMA is a simple moving average, i.e. the mean of the last n samples. Until n
+// samples have been added, it is the mean of all of them. Each Add is O(1)
+// amortized.
+//
+// An SMA is not safe for concurrent use.
+type SMA struct {
+	window []float64
+	pos    int
+	full   bool
+	sum    kahan
+}
+
+// NewSMA returns an SMA over a window of n samples. It panics if n < 1.
+func NewSMA(n int) *SMA {
+	if n < 1 {
+		panic("number: invalid SMA window")
+	}
+	return &SMA{window: make([]float64, n)}
+}
+
+// Add adds a sample x, evicting the oldest one if the window is full, and
+// returns the updated average.
+func (s *SMA) Add(x float64) float64 {
+	// a NaN or Inf cannot be subtracted from the sum, so resum without it
+	resum := false
+	if s.full {
+		if old := s.window[s.pos]; math.IsNaN(old) || math.IsInf(old, 0) {
+			resum = true
+		} else {
+			s.sum.add(-old)
+		}
+	}
+	s.window[s.pos] = x
+	s.sum.add(x)
+
+	s.pos++
+	if s.pos == len(s.window) {
+		s.pos = 0
+		s.full = true
+		// also resum once per window, so that rounding errors do not build up
+		resum = true
+	}
+
+	if resum {
+		s.sum = kahan{}
+		for _, v := range s.window[:s.Len()] {
+			s.sum.add(v)
+		}
+	}
+
+	return s.Value()
+}
+
+// Value returns the current average, or NaN if no sample has been added.
+func (s *SMA) Value() float64 {
+	n := s.Len()
+	if n == 0 {
+		return math.NaN()
+	}
+	return s.sum.value() / float64(n)
+}
+
+// Len returns the number of samples in the window.
+func (s *SMA) Len() int {
+	if s.full {
+		return len(s.window)
+	}
+	return s.pos
+}
+
+// Reset removes all the samples.
+func (s *SMA) Reset() {
+	s.pos, s.full, s.sum = 0, false, kahan{}
+}
+
+// EMA is an exponential moving average, where each sample x updates the
+// average by
+//     value += alpha * (x - value)
+// so the weight of a sample decays geometrically as newer ones are added. The
+// first sample is taken as the initial average as is, instead of being pulled
+// toward 0. Each Add is O(1).
+//
+// An EMA is not safe for concurrent use.
+type EMA struct {
+	alpha float64
+	value float64
+	set   bool
+}
+
+// NewEMA returns an EMA with the smoothing factor alpha, which must be in
+// (0, 1]; the larger, the more responsive to recent samples. It panics if alpha
+// is out of range.
+func NewEMA(alpha float64) *EMA {
+	if !(alpha > 0 && alpha <= 1) {
+		panic("number: invalid EMA alpha")
+	}
+	return &EMA{alpha: alpha}
+}
+
+// NewEMAHalfLife returns an EMA in which the weight of a sample halves after
+// every halfLife newer samples, i.e. with alpha = 1 - 2^(-1/halfLife). For
+// example, with a half-life of 10 samples, the last 10 samples make up half of
+// the average. It panics if halfLife is not positive.
+func NewEMAHalfLife(halfLife float64) *EMA {
+	if !(halfLife > 0) {
+		panic("number: invalid EMA half-life")
+	}
+	return &EMA{alpha: -math.Expm1(-math.Ln2 / halfLife)}
+}
+
+// Alpha returns the smoothing factor of e.
+func (e *EMA) Alpha() float64 {
+	return e.alpha
+}
+
+// Add adds a sample x and returns the updated average.
+func (e *EMA) Add(x float64) float64 {
+	if !e.set {
+		e.value, e.set = x, true
+	} else {
+		e.value += e.alpha * (x - e.value)
+	}
+	return e.value
+}
+
+// Value returns the current average, or NaN if no sample has been added.
+func (e *EMA) Value() float64 {
+	if !e.set {
+		return math.NaN()
+	}
+	return e.value
+}
+
+// Reset removes all the samples.
+func (e *EMA) Reset() {
+	e.value, e.set = 0, false
+}
+
+// MedianFilter is a moving median, i.e. the median of the last n samples,
+// which unlike a moving average is not skewed by occasional outliers. Until n
+// samples have been added, it is the median of all of them. If the number of
+// samples is even, the median is the average of the two middle ones, like
+// Median.
+//
+// The samples are kept in two indexed heaps, the lower half in a max-heap and
+// the upper half in a min-heap, so each Add is O(log n). The median of NaN
+// samples is unspecified.
+//
+// A MedianFilter is not safe for concurrent use.
+type MedianFilter struct {
+	window []*medianNode // ring buffer in insertion order
+	pos    int
+	lo, hi medianHeap
+}
+
+type medianNode struct {
+	v     float64
+	upper bool // in hi rather than lo
+	index int  // in its heap
+}
+
+// medianHeap is a heap of *medianNode which keeps medianNode.index up to date,
+// so that any node can be removed in O(log n).
+type medianHeap struct {
+	nodes []*medianNode
+	max   bool
+}
+
+func (h *medianHeap) Len() int { return len(h.nodes) }
+
+func (h *medianHeap) Less(i, j int) bool {
+	if h.max {
+		return h.nodes[i].v > h.nodes[j].v
+	}
+	return h.nodes[i].v < h.nodes[j].v
+}
+
+func (h *medianHeap) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.nodes[i].index = i
+	h.nodes[j].index = j
+}
+
+func (h *medianHeap) Push(x interface{}) {
+	n := x.(*medianNode)
+	n.index = len(h.nodes)
+	h.nodes = append(h.nodes, n)
+}
+
+func (h *medianHeap) Pop() interface{} {
+	n := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+	return n
+}
+
+func (h *medianHeap) top() float64 {
+	return h.nodes[0].v
+}
+
+// NewMedianFilter returns a MedianFilter over a window of n samples. It panics
+// if n < 1.
+func NewMedianFilter(n int) *MedianFilter {
+	if n < 1 {
+		panic("number: invalid median filter window")
+	}
+	return &MedianFilter{
+		window: make([]*medianNode, 0, n),
+		lo:     medianHeap{max: true},
+	}
+}
+
+// Add adds a sample x, evicting the oldest one if the window is full, and
+// returns the updated median.
+func (f *MedianFilter) Add(x float64) float64 {
+	var n *medianNode
+	if len(f.window) < cap(f.window) {
+		n = new(medianNode)
+		f.window = append(f.window, n)
+	} else {
+		n = f.window[f.pos]
+		if n.upper {
+			heap.Remove(&f.hi, n.index)
+		} else {
+			heap.Remove(&f.lo, n.index)
+		}
+	}
+	f.pos = (f.pos + 1) % cap(f.window)
+
+	n.v = x
+	n.upper = f.lo.Len() > 0 && x > f.lo.top()
+	if n.upper {
+		heap.Push(&f.hi, n)
+	} else {
+		heap.Push(&f.lo, n)
+	}
+
+	// keep len(lo) == len(hi) or len(hi)+1
+	for f.lo.Len() > f.hi.Len()+1 {
+		m := heap.Pop(&f.lo).(*medianNode)
+		m.upper = true
+		heap.Push(&f.hi, m)
+	}
+	for f.hi.Len() > f.lo.Len() {
+		m := heap.Pop(&f.hi).(*medianNode)
+		m.upper = false
+		heap.Push(&f.lo, m)
+	}
+
+	return f.Value()
+}
+
+// Value returns the current median, or NaN if no sample has been added.
+func (f *MedianFilter) Value() float64 {
+	switch {
+	case f.lo.Len() == 0:
+		return math.NaN()
+	case f.lo.Len() > f.hi.Len():
+		return f.lo.top()
+	}
+	return Lerp(f.lo.top(), f.hi.top(), 0.5)
+}
+
+// Len returns the number of samples in the window.
+func (f *MedianFilter) Len() int {
+	return len(f.window)
+}
+
+// Reset removes all the samples.
+func (f *MedianFilter) Reset() {
+	f.window = f.window[:0]
+	f.pos = 0
+	f.lo.nodes = f.lo.nodes[:0]
+	f.hi.nodes = f.hi.nodes[:0]
+}
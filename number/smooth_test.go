@@ -0,0 +1,117 @@
+package number
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func sameFloat(a, b float64) bool {
+	return a == b || math.IsNaN(a) && math.IsNaN(b)
+}
+
+func TestSMA(t *testing.T) {
+	inf, nan := math.Inf(1), math.NaN()
+	tests := []struct {
+		n    int
+		in   []float64
+		want []float64
+	}{
+		{1, []float64{1, 2, 3}, []float64{1, 2, 3}},
+		{3, []float64{1, 2, 3, 4, 5}, []float64{1, 1.5, 2, 3, 4}},
+		{2, []float64{1, inf, 2, 3, 4}, []float64{1, inf, inf, 2.5, 3.5}},
+		{2, []float64{1, nan, 2, 3, 4}, []float64{1, nan, nan, 2.5, 3.5}},
+		{2, []float64{inf, -inf, 1, 2}, []float64{inf, nan, -inf, 1.5}},
+		{3, []float64{nan, 1, 2, 3, 4}, []float64{nan, nan, nan, 2, 3}},
+		{3, []float64{1, 2, inf, 3, 4, 5, 6}, []float64{1, 1.5, inf, inf, inf, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		s := NewSMA(tt.n)
+		for i, x := range tt.in {
+			if got := s.Add(x); !sameFloat(got, tt.want[i]) {
+				t.Errorf("SMA(%d) of %v: Add(%v) = %v, want %v", tt.n, tt.in[:i+1], x, got, tt.want[i])
+			}
+			if got := s.Value(); !sameFloat(got, tt.want[i]) {
+				t.Errorf("SMA(%d) of %v: Value() = %v, want %v", tt.n, tt.in[:i+1], got, tt.want[i])
+			}
+		}
+
+		s.Reset()
+		if s.Len() != 0 || !math.IsNaN(s.Value()) {
+			t.Errorf("SMA(%d) after Reset: Len() = %d, Value() = %v", tt.n, s.Len(), s.Value())
+		}
+	}
+}
+
+func TestSMAAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 50
+	s := NewSMA(n)
+	a := make([]float64, 0, 1000)
+	for i := 0; i < cap(a); i++ {
+		// large values that mostly cancel out, which a naive running sum
+		// would not survive
+		x := rng.NormFloat64()
+		if i%2 == 0 {
+			x += 1e12
+		} else {
+			x -= 1e12
+		}
+		a = append(a, x)
+		got := s.Add(x)
+		want := NeumaierSum(a[Max(0, len(a)-n):]) / float64(Min(len(a), n))
+		if math.Abs(got-want) > 1e-9 {
+			t.Fatalf("SMA(%d) after %d samples = %v, want %v", n, len(a), got, want)
+		}
+	}
+}
+
+func TestEMA(t *testing.T) {
+	e := NewEMA(0.5)
+	if !math.IsNaN(e.Value()) {
+		t.Errorf("empty EMA Value() = %v, want NaN", e.Value())
+	}
+	for i, tt := range []struct{ x, want float64 }{{10, 10}, {20, 15}, {25, 20}, {20, 20}} {
+		if got := e.Add(tt.x); got != tt.want {
+			t.Errorf("EMA(0.5) Add #%d (%v) = %v, want %v", i, tt.x, got, tt.want)
+		}
+	}
+
+	if got := NewEMAHalfLife(1).Alpha(); got != 0.5 {
+		t.Errorf("NewEMAHalfLife(1).Alpha() = %v, want 0.5", got)
+	}
+	// after halfLife samples, the weight of the older ones is halved
+	h := NewEMAHalfLife(10)
+	h.Add(0)
+	for i := 0; i < 10; i++ {
+		h.Add(1)
+	}
+	if got := h.Value(); math.Abs(got-0.5) > 1e-12 {
+		t.Errorf("EMA with half-life 10 after 0 and ten 1s = %v, want 0.5", got)
+	}
+}
+
+// TestMedianFilter checks MedianFilter against Median of a sliding window.
+func TestMedianFilter(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 1; n <= 9; n++ {
+		f := NewMedianFilter(n)
+		var a []float64
+		for i := 0; i < 500; i++ {
+			// few distinct values, so that there are many ties
+			x := float64(rng.Intn(10))
+			if i%3 == 0 {
+				x = rng.NormFloat64()
+			}
+			a = append(a, x)
+
+			// Median interpolates, which may differ in the last bit from
+			// the plain average of the two middle samples
+			want := Median(a[Max(0, len(a)-n):])
+			if got := f.Add(x); math.Abs(got-want) > 1e-15*math.Max(1, math.Abs(want)) {
+				t.Fatalf("MedianFilter(%d) of %v = %v, want %v", n, a[Max(0, len(a)-n):], got, want)
+			}
+		}
+	}
+}
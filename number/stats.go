@@ -0,0 +1,280 @@
+package number
+
+import (
+	"math"
+	"sort"
+)
+
+// QuantileMethod specifies how Quantile estimates a quantile that falls
+// between two observations. The methods and their names match the ones of
+// NumPy's numpy.quantile, and so do the results.
+type QuantileMethod int
+
+const (
+	// QuantileLinear interpolates linearly between the two closest
+	// observations, with the virtual index (n-1)*q. It is the default method
+	// of NumPy, R and Excel's PERCENTILE.INC.
+	QuantileLinear QuantileMethod = iota
+	// QuantileLower takes the lower of the two closest observations.
+	QuantileLower
+	// QuantileHigher takes the higher of the two closest observations.
+	QuantileHigher
+	// QuantileMidpoint takes the average of the two closest observations.
+	QuantileMidpoint
+	// QuantileNearest takes the closest observation, and ties to the one with
+	// an even index.
+	QuantileNearest
+	// QuantileInvertedCDF is method 1 of Hyndman and Fan.
+	QuantileInvertedCDF
+	// QuantileAveragedInvertedCDF is method 2 of Hyndman and Fan.
+	QuantileAveragedInvertedCDF
+	// QuantileClosestObservation is method 3 of Hyndman and Fan.
+	QuantileClosestObservation
+	// QuantileInterpolatedInvertedCDF is method 4 of Hyndman and Fan.
+	QuantileInterpolatedInvertedCDF
+	// QuantileHazen is method 5 of Hyndman and Fan.
+	QuantileHazen
+	// QuantileWeibull is method 6 of Hyndman and Fan. It is the method of
+	// Excel's PERCENTILE.EXC.
+	QuantileWeibull
+	// QuantileMedianUnbiased is method 8 of Hyndman and Fan. It is recommended
+	// by Hyndman and Fan if the distribution is unknown.
+	QuantileMedianUnbiased
+	// QuantileNormalUnbiased is method 9 of Hyndman and Fan.
+	QuantileNormalUnbiased
+)
+
+// Sum returns the sum of a. It is a naive summation, so it may overflow for
+// integers, and its rounding error grows linearly with len(a) for floats. See
+// NeumaierSum and PairwiseSum for accurate float summation.
+func Sum[T Real](a []T) T {
+	var s T
+	for _, v := range a {
+		s += v
+	}
+	return s
+}
+
+// Mean returns the arithmetic mean of a, or NaN if a is empty.
+//
+// The mean is updated incrementally as m += (x-m)/k instead of being computed
+// as Sum(a)/len(a), so it never overflows, even for large integers.
+func Mean[T Real](a []T) float64 {
+	if len(a) == 0 {
+		return math.NaN()
+	}
+
+	var m float64
+	for i, v := range a {
+		m += (float64(v) - m) / float64(i+1)
+	}
+	return m
+}
+
+// Variance returns the variance of a with ddof delta degrees of freedom, i.e.
+// the sum of squared deviations divided by len(a)-ddof, like NumPy's
+// numpy.var. Use ddof 0 for the population variance, and 1 for the unbiased
+// sample variance. The result is NaN if len(a) <= ddof.
+//
+// It uses Welford's online algorithm, which does not suffer from the
+// catastrophic cancellation of the textbook formula E[X^2] - E[X]^2. See
+// StableVariance for an even more accurate one.
+func Variance[T Real](a []T, ddof int) float64 {
+	if len(a) <= ddof {
+		return math.NaN()
+	}
+
+	var m, m2 float64
+	for i, v := range a {
+		x := float64(v)
+		d := x - m
+		m += d / float64(i+1)
+		m2 += d * (x - m)
+	}
+	return m2 / float64(len(a)-ddof)
+}
+
+// StdDev returns the standard deviation of a with ddof delta degrees of
+// freedom, i.e. the square root of Variance(a, ddof).
+func StdDev[T Real](a []T, ddof int) float64 {
+	return math.Sqrt(Variance(a, ddof))
+}
+
+// Median returns the median of a, or NaN if a is empty. If len(a) is even, it
+// is the average of the two middle elements.
+func Median[T Real](a []T) float64 {
+	return Quantile(a, 0.5, QuantileLinear)
+}
+
+// Mode returns the most frequent elements of a in ascending order, and how many
+// times each of them occurs. It returns (nil, 0) if a is empty. NaN elements
+// are ignored.
+func Mode[T Ordered](a []T) (modes []T, count int) {
+	freq := make(map[T]int, len(a))
+	for _, v := range a {
+		// only NaN is not equal to itself
+		if v != v {
+			continue
+		}
+
+		freq[v]++
+		switch c := freq[v]; {
+		case c > count:
+			count = c
+			modes = append(modes[:0], v)
+		case c == count:
+			modes = append(modes, v)
+		}
+	}
+
+	sort.Slice(modes, func(i, j int) bool { return modes[i] < modes[j] })
+	return modes, count
+}
+
+// Quantile returns the q-th quantile of a, where q is in [0, 1], estimated
+// with method. It returns NaN if a is empty, q is out of range, or a contains
+// NaN.
+func Quantile[T Real](a []T, q float64, method QuantileMethod) float64 {
+	s, ok := sortedFloats(a)
+	if !ok || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+	return quantileSorted(s, q, method)
+}
+
+// Percentile is like Quantile, but p is in [0, 100].
+func Percentile[T Real](a []T, p float64, method QuantileMethod) float64 {
+	return Quantile(a, p/100, method)
+}
+
+// FiveNumberSummary is the five-number summary of a sample.
+type FiveNumberSummary struct {
+	Min    float64
+	Q1     float64 // first quartile
+	Median float64
+	Q3     float64 // third quartile
+	Max    float64
+}
+
+// IQR returns the interquartile range, Q3 - Q1.
+func (s FiveNumberSummary) IQR() float64 {
+	return s.Q3 - s.Q1
+}
+
+// Summarize returns the five-number summary of a, whose quartiles are
+// estimated with method. ok is false if a is empty or contains NaN.
+func Summarize[T Real](a []T, method QuantileMethod) (s FiveNumberSummary, ok bool) {
+	sorted, ok := sortedFloats(a)
+	if !ok {
+		return s, false
+	}
+
+	return FiveNumberSummary{
+		Min:    sorted[0],
+		Q1:     quantileSorted(sorted, 0.25, method),
+		Median: quantileSorted(sorted, 0.5, method),
+		Q3:     quantileSorted(sorted, 0.75, method),
+		Max:    sorted[len(sorted)-1],
+	}, true
+}
+
+// sortedFloats returns a sorted copy of a as []float64. ok is false if a is
+// empty or contains NaN.
+func sortedFloats[T Real](a []T) (s []float64, ok bool) {
+	if len(a) == 0 {
+		return nil, false
+	}
+
+	s = make([]float64, len(a))
+	for i, v := range a {
+		if v != v {
+			return nil, false
+		}
+		s[i] = float64(v)
+	}
+	sort.Float64s(s)
+
+	return s, true
+}
+
+// quantileSorted is a port of NumPy's quantile, where s is sorted and not
+// empty, and q is in [0, 1].
+func quantileSorted(s []float64, q float64, method QuantileMethod) float64 {
+	n := float64(len(s))
+	last := len(s) - 1
+
+	// the discontinuous methods pick an observation directly
+	pick := func(i float64) float64 {
+		return s[int(math.Max(0, math.Min(i, float64(last))))]
+	}
+	switch method {
+	case QuantileLower:
+		return pick(math.Floor((n - 1) * q))
+	case QuantileHigher:
+		return pick(math.Ceil((n - 1) * q))
+	case QuantileNearest:
+		return pick(math.RoundToEven((n - 1) * q))
+	case QuantileInvertedCDF:
+		i := n*q - 1
+		if i != math.Floor(i) {
+			i = math.Floor(i) + 1
+		}
+		return pick(i)
+	case QuantileClosestObservation:
+		i := n*q - 1.5
+		if fl := math.Floor(i); i != fl || math.Mod(fl, 2) != 0 {
+			i = fl + 1
+		}
+		return pick(i)
+	}
+
+	// the continuous methods interpolate at a virtual index
+	var index float64
+	switch method {
+	case QuantileAveragedInvertedCDF:
+		index = n*q - 1
+	case QuantileInterpolatedInvertedCDF:
+		index = virtualIndex(n, q, 0, 1)
+	case QuantileHazen:
+		index = virtualIndex(n, q, 0.5, 0.5)
+	case QuantileWeibull:
+		index = virtualIndex(n, q, 0, 0)
+	case QuantileMedianUnbiased:
+		index = virtualIndex(n, q, 1.0/3, 1.0/3)
+	case QuantileNormalUnbiased:
+		index = virtualIndex(n, q, 3.0/8, 3.0/8)
+	default: // QuantileLinear, QuantileMidpoint
+		index = (n - 1) * q
+	}
+
+	switch {
+	case index < 0:
+		return s[0]
+	case index >= float64(last):
+		return s[last]
+	}
+
+	prev := math.Floor(index)
+	gamma := index - prev
+	switch method {
+	case QuantileAveragedInvertedCDF:
+		gamma = 1
+		if index == prev {
+			gamma = 0.5
+		}
+	case QuantileMidpoint:
+		gamma = 0.5
+		if index == prev {
+			gamma = 0
+		}
+	}
+
+	i := int(prev)
+	return Lerp(s[i], s[i+1], gamma)
+}
+
+// virtualIndex returns the 0-based virtual index of the q-th quantile in a
+// sample of size n, for the continuous methods with parameters alpha and beta.
+func virtualIndex(n, q, alpha, beta float64) float64 {
+	return n*q + (alpha + q*(1-alpha-beta)) - 1
+}
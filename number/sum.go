@@ -0,0 +1,166 @@
+package number
+
+import (
+	"math"
+)
+
+// KahanSum returns the sum of a with Kahan compensated summation, which keeps
+// the lost low-order bits of each addition in a compensation term. The error
+// bound is 2ε·Σ|a[i]| regardless of len(a), where ε is the machine epsilon,
+// compared to (n-1)ε·Σ|a[i]| of a naive loop. However, it loses the
+// compensation when an element is larger than the running sum, e.g. the sum of
+// [1, 1e100, 1, -1e100] is 0; use NeumaierSum for such inputs.
+func KahanSum(a []float64) float64 {
+	var k kahan
+	for _, v := range a {
+		k.add(v)
+	}
+	return k.sum
+}
+
+// neumaier is Neumaier's variant of Kahan summation.
+type neumaier struct {
+	sum float64
+	c   float64 // compensation, the lost low-order bits
+}
+
+func (n *neumaier) add(x float64) {
+	t := n.sum + x
+	if math.Abs(n.sum) >= math.Abs(x) {
+		n.c += (n.sum - t) + x
+	} else {
+		n.c += (x - t) + n.sum
+	}
+	n.sum = t
+}
+
+func (n *neumaier) value() float64 {
+	// an Inf or NaN sum makes the compensation NaN
+	if math.IsInf(n.sum, 0) || math.IsNaN(n.sum) {
+		return n.sum
+	}
+	return n.sum + n.c
+}
+
+// NeumaierSum returns the sum of a with Neumaier's improved Kahan summation,
+// which also compensates when an element is larger than the running sum, e.g.
+// the sum of [1, 1e100, 1, -1e100] is 2. It is the most accurate of the
+// summations here and as fast as KahanSum.
+func NeumaierSum(a []float64) float64 {
+	var n neumaier
+	for _, v := range a {
+		n.add(v)
+	}
+	return n.value()
+}
+
+// pairwiseBlock is the length below which PairwiseSum sums naively, which is
+// what NumPy uses as well.
+const pairwiseBlock = 128
+
+// PairwiseSum returns the sum of a by splitting it in halves recursively and
+// summing the halves, so the error bound grows with log2(len(a)) rather than
+// len(a), at almost the cost of a naive loop. It is what numpy.sum does.
+func PairwiseSum(a []float64) float64 {
+	if len(a) <= pairwiseBlock {
+		var s float64
+		for _, v := range a {
+			s += v
+		}
+		return s
+	}
+
+	m := len(a) / 2
+	return PairwiseSum(a[:m]) + PairwiseSum(a[m:])
+}
+
+// DotCompensated returns the dot product of a and b, i.e. Σa[i]*b[i], as
+// accurate as if computed in twice the precision of float64 and then rounded,
+// with the Dot2 algorithm of Ogita, Rump and Oishi. The rounding errors of both
+// the products and the additions are computed exactly with math.FMA and
+// accumulated separately. It panics if a and b have different lengths.
+func DotCompensated(a, b []float64) float64 {
+	if len(a) != len(b) {
+		panic("number: DotCompensated of slices of different lengths")
+	}
+
+	var s, c float64
+	for i := range a {
+		p := a[i] * b[i]
+		pErr := math.FMA(a[i], b[i], -p)
+
+		var sErr float64
+		s, sErr = twoSum(s, p)
+		c += pErr + sErr
+	}
+
+	if math.IsInf(s, 0) || math.IsNaN(s) {
+		return s
+	}
+	return s + c
+}
+
+// twoSum returns a+b and its rounding error, so that s+e is exactly a+b. It
+// is Knuth's error-free TwoSum.
+func twoSum(a, b float64) (s, e float64) {
+	s = a + b
+	z := s - a
+	e = (a - (s - z)) + (b - z)
+	return s, e
+}
+
+// StableMean returns the arithmetic mean of a, or NaN if a is empty. Unlike
+// Mean, it is accurate even for values that mostly cancel each other out: the
+// sum is computed with NeumaierSum, and the result is refined with the
+// compensated sum of the deviations from it, whose rounding errors are
+// summed as well. It falls back to Mean if the sum overflows.
+func StableMean(a []float64) float64 {
+	if len(a) == 0 {
+		return math.NaN()
+	}
+
+	n := float64(len(a))
+	m := NeumaierSum(a) / n
+	if math.IsInf(m, 0) {
+		return Mean(a)
+	}
+	if math.IsNaN(m) {
+		return m
+	}
+
+	// the rounding errors of v - m do not cancel out, since they all come
+	// from the low-order bits of the same m
+	var d neumaier
+	for _, v := range a {
+		dv, e := twoSum(v, -m)
+		d.add(dv)
+		d.add(e)
+	}
+	return m + d.value()/n
+}
+
+// StableVariance returns the variance of a with ddof delta degrees of freedom
+// like Variance, but with the compensated two-pass algorithm: the squared
+// deviations from StableMean are summed with Neumaier summation, including
+// their rounding errors computed with math.FMA, and corrected with the residual
+// sum of the deviations. It is more accurate than Welford's algorithm at the
+// cost of a second pass. The result is NaN if len(a) <= ddof.
+func StableVariance(a []float64, ddof int) float64 {
+	if len(a) <= ddof {
+		return math.NaN()
+	}
+
+	m := StableMean(a)
+	var s, ss neumaier
+	for _, v := range a {
+		d := v - m
+		s.add(d)
+		sq := d * d
+		ss.add(sq)
+		ss.add(math.FMA(d, d, -sq))
+	}
+
+	sum := s.value()
+	v := (ss.value() - sum*sum/float64(len(a))) / float64(len(a)-ddof)
+	return math.Max(v, 0)
+}
@@ -0,0 +1,190 @@
+package number
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+const epsilon = 0x1p-52
+
+func naiveSum(a []float64) float64 {
+	var s float64
+	for _, v := range a {
+		s += v
+	}
+	return s
+}
+
+func exactSum(a []float64) *big.Rat {
+	s := new(big.Rat)
+	for _, v := range a {
+		s.Add(s, new(big.Rat).SetFloat64(v))
+	}
+	return s
+}
+
+// absError returns |got - want|, rounded to a float64.
+func absError(got float64, want *big.Rat) float64 {
+	d := new(big.Rat).SetFloat64(got)
+	d.Sub(d, want)
+	f, _ := d.Abs(d).Float64()
+	return f
+}
+
+func sumAbs(a []float64) float64 {
+	var s float64
+	for _, v := range a {
+		s += math.Abs(v)
+	}
+	return s
+}
+
+// illConditioned returns n values whose sum is much smaller than the sum of
+// their magnitudes, spanning exponents from 1e-scale to 1e+scale.
+func illConditioned(rng *rand.Rand, n, scale int) []float64 {
+	a := make([]float64, n)
+	for i := 0; i < n/2; i++ {
+		v := rng.Float64() * math.Pow(10, float64(rng.Intn(2*scale+1)-scale))
+		a[2*i] = v
+		a[2*i+1] = -v * (1 + rng.Float64()*1e-10)
+	}
+	rng.Shuffle(n, func(i, j int) { a[i], a[j] = a[j], a[i] })
+	return a
+}
+
+func TestSumCancellation(t *testing.T) {
+	a := []float64{1, 1e100, 1, -1e100}
+	if got := naiveSum(a); got != 0 {
+		t.Errorf("naive sum of %v = %v, want 0", a, got)
+	}
+	if got := KahanSum(a); got != 0 {
+		t.Errorf("KahanSum(%v) = %v, want 0 as documented", a, got)
+	}
+	if got := NeumaierSum(a); got != 2 {
+		t.Errorf("NeumaierSum(%v) = %v, want 2", a, got)
+	}
+	if got := DotCompensated(a, []float64{1, 1, 1, 1}); got != 2 {
+		t.Errorf("DotCompensated(%v, 1s) = %v, want 2", a, got)
+	}
+}
+
+func TestSumAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// 0.1 is not exact in binary, so each naive addition rounds
+	tenths := make([]float64, 100000)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+
+	inputs := [][]float64{
+		tenths,
+		illConditioned(rng, 20000, 10),
+		illConditioned(rng, 10000, 100),
+	}
+	for i, a := range inputs {
+		exact := exactSum(a)
+		n, abs := float64(len(a)), sumAbs(a)
+		wantF, _ := exact.Float64()
+
+		naive := absError(naiveSum(a), exact)
+		checks := []struct {
+			name  string
+			got   float64
+			bound float64
+		}{
+			{"KahanSum", KahanSum(a), (2*epsilon + n*epsilon*epsilon) * abs},
+			{"NeumaierSum", NeumaierSum(a), epsilon*math.Abs(wantF) + n*n*epsilon*epsilon*abs},
+			{"PairwiseSum", PairwiseSum(a), (pairwiseBlock + math.Log2(n)) * epsilon * abs},
+		}
+		for _, c := range checks {
+			err := absError(c.got, exact)
+			if err > c.bound {
+				t.Errorf("input %d: %s error %g, bound %g", i, c.name, err, c.bound)
+			}
+			if err > naive {
+				t.Errorf("input %d: %s error %g, larger than the naive %g", i, c.name, err, naive)
+			}
+		}
+
+		// the compensated sums are much better than the naive one
+		if err := absError(NeumaierSum(a), exact); naive > 0 && err > naive/100 {
+			t.Errorf("input %d: NeumaierSum error %g, not much better than the naive %g", i, err, naive)
+		}
+	}
+}
+
+func TestDotCompensated(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{10, 1000, 20000} {
+		a := illConditioned(rng, n, 20)
+		b := make([]float64, n)
+		exact := new(big.Rat)
+		var naive, abs float64
+		for i := range b {
+			b[i] = 1 + rng.Float64()*1e-8
+			p := new(big.Rat).SetFloat64(a[i])
+			exact.Add(exact, p.Mul(p, new(big.Rat).SetFloat64(b[i])))
+			naive += a[i] * b[i]
+			abs += math.Abs(a[i] * b[i])
+		}
+		wantF, _ := exact.Float64()
+
+		// as if computed with twice the precision and then rounded
+		gamma := float64(n) * epsilon / (1 - float64(n)*epsilon)
+		bound := epsilon*math.Abs(wantF) + gamma*gamma*abs
+		err := absError(DotCompensated(a, b), exact)
+		if err > bound {
+			t.Errorf("n %d: DotCompensated error %g, bound %g", n, err, bound)
+		}
+		if nerr := absError(naive, exact); err > nerr {
+			t.Errorf("n %d: DotCompensated error %g, larger than the naive %g", n, err, nerr)
+		}
+	}
+}
+
+func TestStableVariance(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// a large offset makes the naive sum of squares cancel catastrophically
+	for _, offset := range []float64{0, 1e6, 1e9, 1e12} {
+		a := make([]float64, 10000)
+		var sum, sumSq float64
+		for i := range a {
+			a[i] = offset + rng.NormFloat64()
+			sum += a[i]
+			sumSq += a[i] * a[i]
+		}
+
+		n := big.NewRat(int64(len(a)), 1)
+		mean := exactSum(a)
+		mean.Quo(mean, n)
+		if got := StableMean(a); absError(got, mean) > epsilon*math.Abs(got) {
+			t.Errorf("offset %g: StableMean = %v, want %s", offset, got, mean.FloatString(20))
+		}
+		ss := new(big.Rat)
+		for _, v := range a {
+			d := new(big.Rat).SetFloat64(v)
+			d.Sub(d, mean)
+			ss.Add(ss, d.Mul(d, d))
+		}
+
+		for _, ddof := range []int{0, 1} {
+			exact := new(big.Rat).Quo(ss, big.NewRat(int64(len(a)-ddof), 1))
+			want, _ := exact.Float64()
+
+			got := StableVariance(a, ddof)
+			err := absError(got, exact)
+			if err > 4*epsilon*want {
+				t.Errorf("offset %g, ddof %d: StableVariance = %v, want %v", offset, ddof, got, want)
+			}
+			naive := (sumSq - sum*sum/float64(len(a))) / float64(len(a)-ddof)
+			if nerr := absError(naive, exact); err > nerr {
+				t.Errorf("offset %g, ddof %d: StableVariance error %g, larger than the naive %g", offset, ddof, err, nerr)
+			}
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package number
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// DefaultCompression is the compression used by NewTDigest when the given one
+// is not positive and finite.
+const DefaultCompression = 100
+
+var ErrInvalidTDigest = errors.New("invalid t-digest encoding")
+
+const tdigestVersion = 1
+
+type centroid struct {
+	mean, weight float64
+}
+
+// TDigest is a t-digest, a mergeable sketch for estimating quantiles of an
+// unbounded stream, as described in "Computing Extremely Accurate Quantiles
+// Using t-Digests" by Ted Dunning and Otmar Ertl. This is the merging variant
+// with the arcsine scale function k1.
+//
+// The size of a TDigest is bounded by its compression δ, regardless of the
+// number of samples. The error of a quantile estimate is roughly proportional
+// to sqrt(q*(1-q))/δ, so it is much more accurate at the tails than around the
+// median.
+//
+// A TDigest must be created with NewTDigest. It is not safe for concurrent
+// use, not even Quantile and CDF, which may compact pending samples.
+type TDigest struct {
+	compression float64
+	centroids   []centroid // sorted by mean
+	buffer      []centroid // pending samples, not sorted
+	count       float64    // total weight of centroids and buffer
+	min, max    float64
+}
+
+// NewTDigest returns an empty TDigest with the given compression. A larger
+// compression gives more accurate estimates at the cost of memory. 100 is a
+// reasonable default. A compression that is not positive and finite is
+// replaced with DefaultCompression.
+func NewTDigest(compression float64) *TDigest {
+	if !(compression > 0) || math.IsInf(compression, 1) {
+		compression = DefaultCompression
+	}
+
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Compression returns the compression of t.
+func (t *TDigest) Compression() float64 {
+	return t.compression
+}
+
+// Count returns the total weight of the samples added to t.
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Add adds a sample x with weight 1.
+func (t *TDigest) Add(x float64) {
+	t.AddWeighted(x, 1)
+}
+
+// AddWeighted adds a sample x with weight w. NaN samples and non-positive
+// weights are ignored.
+func (t *TDigest) AddWeighted(x, w float64) {
+	if math.IsNaN(x) || !(w > 0) {
+		return
+	}
+
+	t.buffer = append(t.buffer, centroid{x, w})
+	t.count += w
+	if x < t.min {
+		t.min = x
+	}
+	if x > t.max {
+		t.max = x
+	}
+
+	if len(t.buffer) >= int(5*t.compression) {
+		t.compress()
+	}
+}
+
+// Merge adds all the samples of o to t. o is not modified. The compression of
+// t is kept.
+func (t *TDigest) Merge(o *TDigest) {
+	if o.count == 0 {
+		return
+	}
+
+	t.buffer = append(t.buffer, o.centroids...)
+	t.buffer = append(t.buffer, o.buffer...)
+	t.count += o.count
+	if o.min < t.min {
+		t.min = o.min
+	}
+	if o.max > t.max {
+		t.max = o.max
+	}
+
+	t.compress()
+}
+
+// k is the scale function k1.
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// compress merges the buffer into the centroids.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := make([]centroid, 0, len(t.centroids)+len(t.buffer))
+	all = append(all, t.centroids...)
+	all = append(all, t.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	// merge neighbors greedily as long as the merged centroid spans at most 1
+	// in k-space; writing to out never overtakes reading from all
+	out := all[:0]
+	cur := all[0]
+	soFar := 0.0
+	kLeft := t.k(0)
+	for _, c := range all[1:] {
+		if t.k((soFar+cur.weight+c.weight)/t.count)-kLeft <= 1 {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+
+		out = append(out, cur)
+		soFar += cur.weight
+		kLeft = t.k(soFar / t.count)
+		cur = c
+	}
+
+	t.centroids = append(out, cur)
+	t.buffer = t.buffer[:0]
+}
+
+// Quantile returns the estimated q-th quantile, where q is in [0, 1]. It
+// returns NaN if t is empty or q is out of range.
+func (t *TDigest) Quantile(q float64) float64 {
+	if t.count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+
+	t.compress()
+	c := t.centroids
+	switch {
+	case q == 0:
+		return t.min
+	case q == 1:
+		return t.max
+	case len(c) == 1:
+		return c[0].mean
+	}
+
+	// each centroid is considered to be centered at its mean, with half of its
+	// weight on each side
+	index := q * t.count
+	if index < c[0].weight/2 {
+		return t.min + (c[0].mean-t.min)*index/(c[0].weight/2)
+	}
+
+	soFar := c[0].weight / 2
+	for i := 0; i < len(c)-1; i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if soFar+dw > index {
+			return Lerp(c[i].mean, c[i+1].mean, (index-soFar)/dw)
+		}
+		soFar += dw
+	}
+
+	last := c[len(c)-1]
+	return last.mean + (t.max-last.mean)*math.Min(1, (index-soFar)/(last.weight/2))
+}
+
+// CDF returns the estimated fraction of the samples that are less than or
+// equal to x. It returns NaN if t is empty.
+func (t *TDigest) CDF(x float64) float64 {
+	if t.count == 0 || math.IsNaN(x) {
+		return math.NaN()
+	}
+
+	t.compress()
+	c := t.centroids
+	switch {
+	case x < t.min:
+		return 0
+	case x >= t.max:
+		return 1
+	case len(c) == 1:
+		// t.min < t.max, interpolate within the only centroid
+		return (x - t.min) / (t.max - t.min)
+	}
+
+	if x < c[0].mean {
+		return c[0].weight / 2 * (x - t.min) / (c[0].mean - t.min) / t.count
+	}
+
+	soFar := c[0].weight / 2
+	for i := 0; i < len(c)-1; i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if x < c[i+1].mean {
+			return (soFar + dw*(x-c[i].mean)/(c[i+1].mean-c[i].mean)) / t.count
+		}
+		soFar += dw
+	}
+
+	last := c[len(c)-1]
+	return (soFar + last.weight/2*(x-last.mean)/(t.max-last.mean)) / t.count
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is compact
+// and portable, so it can be shipped to an aggregator and merged there.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+
+	b := make([]byte, 0, 1+3*8+binary.MaxVarintLen64+16*len(t.centroids))
+	b = append(b, tdigestVersion)
+	b = appendFloat64(b, t.compression)
+	b = appendFloat64(b, t.min)
+	b = appendFloat64(b, t.max)
+	var n [binary.MaxVarintLen64]byte
+	b = append(b, n[:binary.PutUvarint(n[:], uint64(len(t.centroids)))]...)
+	for _, c := range t.centroids {
+		b = appendFloat64(b, c.mean)
+		b = appendFloat64(b, c.weight)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It fails with
+// ErrInvalidTDigest if data is truncated, or if the centroids are not sorted
+// by mean, or have NaN means or non-positive weights.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+3*8 || data[0] != tdigestVersion {
+		return ErrInvalidTDigest
+	}
+
+	compression := readFloat64(data[1:])
+	min := readFloat64(data[9:])
+	max := readFloat64(data[17:])
+	data = data[25:]
+
+	n, l := binary.Uvarint(data)
+	if l <= 0 || !(compression > 0) || math.IsInf(compression, 1) {
+		return ErrInvalidTDigest
+	}
+	// check n against the remaining length before multiplying it, which
+	// could overflow
+	data = data[l:]
+	if n > uint64(len(data))/16 || uint64(len(data)) != 16*n {
+		return ErrInvalidTDigest
+	}
+
+	centroids := make([]centroid, n)
+	count := 0.0
+	for i := range centroids {
+		c := centroid{readFloat64(data[16*i:]), readFloat64(data[16*i+8:])}
+		if math.IsNaN(c.mean) || !(c.weight > 0) || i > 0 && c.mean < centroids[i-1].mean {
+			return ErrInvalidTDigest
+		}
+		centroids[i] = c
+		count += c.weight
+	}
+	if n > 0 && !(min <= centroids[0].mean && max >= centroids[n-1].mean) {
+		return ErrInvalidTDigest
+	}
+
+	*t = TDigest{
+		compression: compression,
+		centroids:   centroids,
+		count:       count,
+		min:         min,
+		max:         max,
+	}
+
+	return nil
+}
+
+func appendFloat64(b []byte, f float64) []byte {
+	var u [8]byte
+	binary.LittleEndian.PutUint64(u[:], math.Float64bits(f))
+	return append(b, u[:]...)
+}
+
+func readFloat64(b []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
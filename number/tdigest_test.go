@@ -0,0 +1,108 @@
+package number
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTDigestBinary(t *testing.T) {
+	d := NewTDigest(50)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		d.Add(rng.NormFloat64())
+	}
+
+	data, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e TDigest
+	if err := e.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []float64{0, 0.01, 0.5, 0.99, 1} {
+		if got, want := e.Quantile(q), d.Quantile(q); got != want {
+			t.Errorf("Quantile(%v) after round trip = %v, want %v", q, got, want)
+		}
+	}
+
+	// a centroid count whose size in bytes overflows
+	var huge [binary.MaxVarintLen64]byte
+	bad := append([]byte(nil), data[:25]...)
+	bad = append(bad, huge[:binary.PutUvarint(huge[:], 1<<60)]...)
+	if err := e.UnmarshalBinary(bad); err != ErrInvalidTDigest {
+		t.Errorf("UnmarshalBinary with 1<<60 centroids: %v, want %v", err, ErrInvalidTDigest)
+	}
+
+	for _, f := range []func(c []centroid){
+		func(c []centroid) { c[1].weight = math.NaN() },
+		func(c []centroid) { c[1].weight = -1 },
+		func(c []centroid) { c[1].weight = 0 },
+		func(c []centroid) { c[1].mean = math.NaN() },
+		func(c []centroid) { c[0], c[1] = c[1], c[0] },
+		func(c []centroid) { c[0].mean = math.Inf(-1) },
+	} {
+		o := NewTDigest(50)
+		o.Merge(d)
+		o.compress()
+		f(o.centroids)
+		data, _ := o.MarshalBinary()
+		if err := e.UnmarshalBinary(data); err != ErrInvalidTDigest {
+			t.Errorf("UnmarshalBinary of invalid centroids %v: %v, want %v", o.centroids[:2], err, ErrInvalidTDigest)
+		}
+	}
+}
+
+func TestNewTDigestCompression(t *testing.T) {
+	for _, c := range []float64{0, -1, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		d := NewTDigest(c)
+		if d.Compression() != DefaultCompression {
+			t.Errorf("NewTDigest(%v).Compression() = %v, want %v", c, d.Compression(), DefaultCompression)
+		}
+		d.Add(1)
+		data, _ := d.MarshalBinary()
+		if err := new(TDigest).UnmarshalBinary(data); err != nil {
+			t.Errorf("UnmarshalBinary of NewTDigest(%v): %v", c, err)
+		}
+	}
+}
+
+func FuzzTDigestUnmarshalBinary(f *testing.F) {
+	for _, n := range []int{0, 1, 2, 1000} {
+		d := NewTDigest(20)
+		for i := 0; i < n; i++ {
+			d.Add(float64(i * i % 37))
+		}
+		data, _ := d.MarshalBinary()
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var d TDigest
+		if d.UnmarshalBinary(data) != nil {
+			return
+		}
+
+		for _, q := range []float64{0, 0.25, 0.5, 0.75, 1} {
+			d.Quantile(q)
+			d.CDF(q)
+		}
+		// data may have an overlong varint, so compare the canonical encodings
+		data, _ = d.MarshalBinary()
+		var e TDigest
+		if err := e.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(%x): %v", data, err)
+		}
+		if again, _ := e.MarshalBinary(); !bytes.Equal(again, data) {
+			t.Fatalf("MarshalBinary after UnmarshalBinary(%x) = %x", data, again)
+		}
+
+		m := NewTDigest(20)
+		m.Merge(&d)
+		m.Add(1)
+		m.Quantile(0.5)
+	})
+}
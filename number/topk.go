@@ -0,0 +1,289 @@
+package number
+
+import (
+	"container/heap"
+	"math/bits"
+	"sort"
+)
+
+// TopK returns the indices and the values of the k largest elements of a, in
+// descending order. Equal elements are ordered by index, so the result is the
+// same as the first k of a stable descending sort. If a has fewer than k
+// elements, all of them are returned. NaN elements are ignored.
+//
+// It selects the k elements with introselect in O(n) on average, where n is
+// len(a), and sorts only them, so it is O(n + k*log(k)) rather than O(n*log(n))
+// of a full sort. a is not modified.
+func TopK[T Ordered](a []T, k int) (idx []int, vals []T) {
+	return selectK(a, k, false)
+}
+
+// BottomK is like TopK, but returns the k smallest elements of a in ascending
+// order.
+func BottomK[T Ordered](a []T, k int) (idx []int, vals []T) {
+	return selectK(a, k, true)
+}
+
+func selectK[T Ordered](a []T, k int, bottom bool) ([]int, []T) {
+	if k <= 0 {
+		return nil, nil
+	}
+
+	idx := make([]int, 0, len(a))
+	for i, v := range a {
+		// only NaN is not equal to itself
+		if v == v {
+			idx = append(idx, i)
+		}
+	}
+
+	s := &indexSorter[T]{a: a, idx: idx, bottom: bottom}
+	if k < len(idx) {
+		quickselect(s, k-1)
+		s.idx = s.idx[:k]
+	}
+	sort.Sort(s)
+
+	vals := make([]T, len(s.idx))
+	for i, j := range s.idx {
+		vals[i] = a[j]
+	}
+	return s.idx, vals
+}
+
+// indexSorter orders indices of a by their values, descending unless bottom,
+// and then by the indices themselves, which is a strict total order.
+type indexSorter[T Ordered] struct {
+	a      []T
+	idx    []int
+	bottom bool
+}
+
+func (s *indexSorter[T]) Len() int      { return len(s.idx) }
+func (s *indexSorter[T]) Swap(i, j int) { s.idx[i], s.idx[j] = s.idx[j], s.idx[i] }
+
+func (s *indexSorter[T]) Less(i, j int) bool {
+	return rankBefore(s.a[s.idx[i]], s.idx[i], s.a[s.idx[j]], s.idx[j], s.bottom)
+}
+
+// rankBefore reports whether the value x at index i ranks before the value y at
+// index j in a top-k, or a bottom-k if bottom.
+func rankBefore[T Ordered](x T, i int, y T, j int, bottom bool) bool {
+	switch {
+	case x == y:
+		return i < j
+	case bottom:
+		return x < y
+	}
+	return x > y
+}
+
+// NthElement partially sorts a in place such that a[n] is the element that
+// would be there if a were sorted in ascending order, every element before it
+// is <= a[n], and every element after it is >= a[n], like C++'s
+// std::nth_element. NaN elements are treated as larger than everything else.
+// It is O(len(a)) on average and O(len(a)*log(len(a))) in the worst case.
+//
+// For example, NthElement(a, len(a)/2) puts the median of an odd-length a in
+// the middle. It panics if n is out of range.
+func NthElement[T Ordered](a []T, n int) {
+	if n < 0 || n >= len(a) {
+		panic("number: NthElement index out of range")
+	}
+	quickselect(nanLastSorter[T](a), n)
+}
+
+// nanLastSorter sorts in ascending order with NaN last.
+type nanLastSorter[T Ordered] []T
+
+func (s nanLastSorter[T]) Len() int      { return len(s) }
+func (s nanLastSorter[T]) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s nanLastSorter[T]) Less(i, j int) bool {
+	return s[i] < s[j] || (s[i] == s[i] && s[j] != s[j])
+}
+
+// quickselect partially sorts data such that the element at k is in its
+// sorted position, with everything before it not greater and everything after
+// it not less. It is a quickselect with median-of-three pivots that falls back
+// to sorting when the partitions keep being unbalanced, i.e. introselect.
+func quickselect(data sort.Interface, k int) {
+	lo, hi := 0, data.Len()
+	limit := 2 * bits.Len(uint(hi))
+	for hi-lo > 12 {
+		if limit == 0 {
+			sort.Sort(subSorter{data, lo, hi - lo})
+			return
+		}
+		limit--
+
+		p := partition(data, lo, hi)
+		switch {
+		case k < p:
+			hi = p
+		case k > p:
+			lo = p + 1
+		default:
+			return
+		}
+	}
+
+	// insertion sort for short ranges
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+	}
+}
+
+// partition partitions data[lo:hi] around the median of its first, middle and
+// last elements, and returns the final index of the pivot.
+func partition(data sort.Interface, lo, hi int) int {
+	m, last := lo+(hi-lo)/2, hi-1
+	if data.Less(m, lo) {
+		data.Swap(m, lo)
+	}
+	if data.Less(last, m) {
+		data.Swap(last, m)
+		if data.Less(m, lo) {
+			data.Swap(m, lo)
+		}
+	}
+
+	// move the pivot to the end, then Lomuto partition
+	data.Swap(m, last)
+	p := lo
+	for i := lo; i < last; i++ {
+		if data.Less(i, last) {
+			data.Swap(i, p)
+			p++
+		}
+	}
+	data.Swap(p, last)
+	return p
+}
+
+// subSorter is data[off:off+n].
+type subSorter struct {
+	data sort.Interface
+	off  int
+	n    int
+}
+
+func (s subSorter) Len() int           { return s.n }
+func (s subSorter) Less(i, j int) bool { return s.data.Less(s.off+i, s.off+j) }
+func (s subSorter) Swap(i, j int)      { s.data.Swap(s.off+i, s.off+j) }
+
+// StreamTopK keeps the k largest (or smallest) values added to it, for inputs
+// that are too large to be held in memory, or that arrive one at a time. It
+// holds them in a heap, so each Add is O(log k) and the memory is O(k).
+//
+// Each value is identified by its index, i.e. the number of values added
+// before it, which can be used to look up what the value belongs to, e.g. the
+// name of a file by its size. NaN values are ignored, but still take an index.
+//
+// A StreamTopK is not safe for concurrent use.
+type StreamTopK[T Ordered] struct {
+	h topKHeap[T]
+	k int
+	n int
+}
+
+type topKItem[T Ordered] struct {
+	v T
+	i int
+}
+
+// topKHeap has the item that ranks last at the root, so that it is the one to
+// be replaced by a better one.
+type topKHeap[T Ordered] struct {
+	items  []topKItem[T]
+	bottom bool
+}
+
+func (h *topKHeap[T]) Len() int      { return len(h.items) }
+func (h *topKHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *topKHeap[T]) Less(i, j int) bool {
+	x, y := h.items[i], h.items[j]
+	return rankBefore(y.v, y.i, x.v, x.i, h.bottom)
+}
+
+func (h *topKHeap[T]) Push(x interface{}) {
+	h.items = append(h.items, x.(topKItem[T]))
+}
+
+func (h *topKHeap[T]) Pop() interface{} {
+	it := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return it
+}
+
+// NewStreamTopK returns a StreamTopK that keeps the k largest values. It panics
+// if k is negative.
+func NewStreamTopK[T Ordered](k int) *StreamTopK[T] {
+	return newStreamTopK[T](k, false)
+}
+
+// NewStreamBottomK returns a StreamTopK that keeps the k smallest values. It
+// panics if k is negative.
+func NewStreamBottomK[T Ordered](k int) *StreamTopK[T] {
+	return newStreamTopK[T](k, true)
+}
+
+func newStreamTopK[T Ordered](k int, bottom bool) *StreamTopK[T] {
+	if k < 0 {
+		panic("number: negative k for StreamTopK")
+	}
+	return &StreamTopK[T]{
+		h: topKHeap[T]{items: make([]topKItem[T], 0, k), bottom: bottom},
+		k: k,
+	}
+}
+
+// Add adds a value v with the next index.
+func (s *StreamTopK[T]) Add(v T) {
+	i := s.n
+	s.n++
+	if v != v || s.k == 0 {
+		return
+	}
+
+	switch {
+	case len(s.h.items) < s.k:
+		heap.Push(&s.h, topKItem[T]{v, i})
+	case rankBefore(v, i, s.h.items[0].v, s.h.items[0].i, s.h.bottom):
+		s.h.items[0] = topKItem[T]{v, i}
+		heap.Fix(&s.h, 0)
+	}
+}
+
+// Count returns the number of values added, including the ones that have been
+// dropped.
+func (s *StreamTopK[T]) Count() int {
+	return s.n
+}
+
+// Result returns the indices and the values of the kept values, in the same
+// order as TopK or BottomK would on the whole input. It does not change s, so
+// more values can be added afterwards.
+func (s *StreamTopK[T]) Result() (idx []int, vals []T) {
+	items := make([]topKItem[T], len(s.h.items))
+	copy(items, s.h.items)
+	sort.Slice(items, func(i, j int) bool {
+		return rankBefore(items[i].v, items[i].i, items[j].v, items[j].i, s.h.bottom)
+	})
+
+	idx = make([]int, len(items))
+	vals = make([]T, len(items))
+	for i, it := range items {
+		idx[i], vals[i] = it.i, it.v
+	}
+	return idx, vals
+}
+
+// Reset removes all the values and restarts the indices from 0.
+func (s *StreamTopK[T]) Reset() {
+	s.h.items = s.h.items[:0]
+	s.n = 0
+}
@@ -0,0 +1,144 @@
+package number
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// topKInputs returns random slices with many ties and NaNs, as well as sorted,
+// reversed and constant ones, which are the bad cases of quickselect.
+func topKInputs(rng *rand.Rand) [][]float64 {
+	var inputs [][]float64
+	for _, n := range []int{0, 1, 2, 5, 13, 50, 200, 1000} {
+		for r := 0; r < 10; r++ {
+			a := make([]float64, n)
+			distinct := 1 + rng.Intn(n+1)
+			for i := range a {
+				a[i] = float64(rng.Intn(distinct))
+				if rng.Intn(10) == 0 {
+					a[i] = math.NaN()
+				}
+			}
+			inputs = append(inputs, a)
+		}
+
+		sorted, reversed, constant := make([]float64, n), make([]float64, n), make([]float64, n)
+		for i := range sorted {
+			sorted[i], reversed[i], constant[i] = float64(i), float64(n-i), 7
+		}
+		inputs = append(inputs, sorted, reversed, constant)
+	}
+	return inputs
+}
+
+// stableTopK returns the first k elements of a stable sort of the non-NaN
+// elements of a, descending unless bottom.
+func stableTopK(a []float64, k int, bottom bool) []int {
+	var idx []int
+	for i, v := range a {
+		if !math.IsNaN(v) {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		if bottom {
+			return a[idx[i]] < a[idx[j]]
+		}
+		return a[idx[i]] > a[idx[j]]
+	})
+	if k < 0 {
+		k = 0
+	}
+	if k < len(idx) {
+		idx = idx[:k]
+	}
+	return idx
+}
+
+func checkTopK(t *testing.T, name string, a []float64, k int, bottom bool, idx []int, vals []float64) {
+	t.Helper()
+
+	want := stableTopK(a, k, bottom)
+	if len(idx) != len(want) || len(vals) != len(want) {
+		t.Fatalf("%s(%v, %d) = %v, %v, want indices %v", name, a, k, idx, vals, want)
+	}
+	for i := range want {
+		if idx[i] != want[i] || vals[i] != a[want[i]] {
+			t.Fatalf("%s(%v, %d) = %v, %v, want indices %v", name, a, k, idx, vals, want)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, a := range topKInputs(rng) {
+		orig := append([]float64(nil), a...)
+		for _, k := range []int{-1, 0, 1, 2, len(a) / 2, len(a) - 1, len(a), len(a) + 1} {
+			for _, bottom := range []bool{false, true} {
+				name, f := "TopK", TopK[float64]
+				s := NewStreamTopK[float64](Max(k, 0))
+				if bottom {
+					name, f = "BottomK", BottomK[float64]
+					s = NewStreamBottomK[float64](Max(k, 0))
+				}
+
+				idx, vals := f(a, k)
+				checkTopK(t, name, a, k, bottom, idx, vals)
+
+				for _, v := range a {
+					s.Add(v)
+				}
+				idx, vals = s.Result()
+				checkTopK(t, "Stream"+name, a, k, bottom, idx, vals)
+				if s.Count() != len(a) {
+					t.Fatalf("Stream%s Count() = %d, want %d", name, s.Count(), len(a))
+				}
+			}
+		}
+
+		for i := range a {
+			if !sameFloat(a[i], orig[i]) {
+				t.Fatalf("TopK modified its input %v", orig)
+			}
+		}
+	}
+}
+
+func nanLastLess(x, y float64) bool {
+	return x < y || !math.IsNaN(x) && math.IsNaN(y)
+}
+
+func TestNthElement(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, a := range topKInputs(rng) {
+		sorted := append([]float64(nil), a...)
+		sort.SliceStable(sorted, func(i, j int) bool { return nanLastLess(sorted[i], sorted[j]) })
+
+		for _, n := range []int{0, len(a) / 3, len(a) / 2, len(a) - 1} {
+			if n < 0 || n >= len(a) {
+				continue
+			}
+			b := append([]float64(nil), a...)
+			NthElement(b, n)
+
+			if !sameFloat(b[n], sorted[n]) {
+				t.Fatalf("NthElement(%v, %d) put %v at %d, want %v", a, n, b[n], n, sorted[n])
+			}
+			for i, v := range b {
+				if i < n && nanLastLess(b[n], v) || i > n && nanLastLess(v, b[n]) {
+					t.Fatalf("NthElement(%v, %d) = %v, %v is on the wrong side of %v", a, n, b, v, b[n])
+				}
+			}
+
+			// b is a permutation of a
+			sort.SliceStable(b, func(i, j int) bool { return nanLastLess(b[i], b[j]) })
+			for i := range b {
+				if !sameFloat(b[i], sorted[i]) {
+					t.Fatalf("NthElement(%v, %d) is not a permutation of its input", a, n)
+				}
+			}
+		}
+	}
+}
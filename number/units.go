@@ -0,0 +1,408 @@
+package number
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrDimensionMismatch = errors.New("dimension mismatch")
+
+// Dimension is the physical dimension of a Quantity, as the exponents of the
+// base dimensions, e.g. a speed is Dimension{Length: 1, Time: -1}.
+type Dimension struct {
+	Length, Mass, Time, Data int8
+}
+
+var (
+	Dimensionless = Dimension{}
+	DimLength     = Dimension{Length: 1}
+	DimMass       = Dimension{Mass: 1}
+	DimTime       = Dimension{Time: 1}
+	DimData       = Dimension{Data: 1}
+	DimDataRate   = Dimension{Data: 1, Time: -1}
+)
+
+func (d Dimension) mul(e Dimension) Dimension {
+	return Dimension{d.Length + e.Length, d.Mass + e.Mass, d.Time + e.Time, d.Data + e.Data}
+}
+
+func (d Dimension) quo(e Dimension) Dimension {
+	return Dimension{d.Length - e.Length, d.Mass - e.Mass, d.Time - e.Time, d.Data - e.Data}
+}
+
+// String returns d in terms of the base units, e.g. "m", "B/s" or "m/s^2". It
+// is empty for Dimensionless.
+func (d Dimension) String() string {
+	var num, den []string
+	for _, b := range []struct {
+		symbol string
+		exp    int8
+	}{{"m", d.Length}, {"kg", d.Mass}, {"s", d.Time}, {"B", d.Data}} {
+		e := b.exp
+		s := &num
+		if e < 0 {
+			e, s = -e, &den
+		}
+		switch {
+		case e == 1:
+			*s = append(*s, b.symbol)
+		case e > 1:
+			*s = append(*s, b.symbol+"^"+strconv.Itoa(int(e)))
+		}
+	}
+
+	str := strings.Join(num, "·")
+	if len(num) == 0 && len(den) > 0 {
+		str = "1"
+	}
+	if len(den) > 0 {
+		str += "/" + strings.Join(den, "·")
+	}
+	return str
+}
+
+// Unit is a unit of measurement, such as km, MiB or MB/s.
+type Unit struct {
+	Symbol string
+	Factor float64 // the size of the unit in base units, i.e. m, kg, s and B
+	Dim    Dimension
+}
+
+var units = buildUnits()
+
+func buildUnits() map[string]Unit {
+	m := make(map[string]Unit)
+	add := func(symbol string, factor float64, dim Dimension) {
+		m[symbol] = Unit{symbol, factor, dim}
+	}
+
+	for _, p := range siPrefixes {
+		f := math.Pow(1000, float64(p.exp))
+		add(p.symbol+"m", f, DimLength)
+		add(p.symbol+"g", f/1000, DimMass)
+		if p.exp <= 0 {
+			add(p.symbol+"s", f, DimTime)
+		}
+		if p.exp >= 0 {
+			add(p.symbol+"B", f, DimData)
+			add(p.symbol+"bit", f/8, DimData)
+			add(p.symbol+"bps", f/8, DimDataRate)
+		}
+	}
+	for i, p := range []string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei", "Zi", "Yi"} {
+		f := math.Pow(1024, float64(i+1))
+		add(p+"B", f, DimData)
+		add(p+"bit", f/8, DimData)
+	}
+
+	// common aliases and non-SI units
+	add("um", 1e-6, DimLength)
+	add("cm", 0.01, DimLength)
+	add("in", 0.0254, DimLength)
+	add("ft", 0.3048, DimLength)
+	add("yd", 0.9144, DimLength)
+	add("mi", 1609.344, DimLength)
+	add("ug", 1e-9, DimMass)
+	add("t", 1000, DimMass)
+	add("lb", 0.45359237, DimMass)
+	add("oz", 0.45359237/16, DimMass)
+	add("us", 1e-6, DimTime)
+	add("min", 60, DimTime)
+	add("h", 3600, DimTime)
+	add("d", 86400, DimTime)
+	add("KB", 1000, DimData)
+	add("Kbit", 125, DimData)
+	add("Kbps", 125, DimDataRate)
+	return m
+}
+
+// ParseUnit parses a unit symbol, such as "km", "µs", "MiB", "Mbit" or "lb", or
+// a quotient of them, such as "MB/s" or "m/s". Symbols are case-sensitive,
+// except that "u" is accepted for micro, and "K" for kilo in data units. Data
+// sizes with SI prefixes are decimal, e.g. 1 kB = 1000 B, and those with IEC
+// prefixes are binary, e.g. 1 KiB = 1024 B.
+func ParseUnit(s string) (Unit, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '/'); i >= 0 {
+		num, err := ParseUnit(s[:i])
+		if err != nil {
+			return Unit{}, err
+		}
+		den, err := ParseUnit(s[i+1:])
+		if err != nil {
+			return Unit{}, err
+		}
+		return Unit{s, num.Factor / den.Factor, num.Dim.quo(den.Dim)}, nil
+	}
+
+	// Greek small letter mu for micro sign
+	u, ok := units[strings.Replace(s, "μ", "µ", 1)]
+	if !ok {
+		return Unit{}, fmt.Errorf("parsing unit %q: unknown unit", s)
+	}
+	u.Symbol = s
+	return u, nil
+}
+
+// MustParseUnit is like ParseUnit but panics if s cannot be parsed.
+func MustParseUnit(s string) Unit {
+	u, err := ParseUnit(s)
+	if err != nil {
+		panic("number: " + err.Error())
+	}
+	return u
+}
+
+// Quantity is a value with a dimension, such as 5 km or 12.5 MB/s, stored in
+// base units, i.e. m, kg, s and B. Quantities of the same dimension can be
+// added, subtracted and compared, and any quantities can be multiplied and
+// divided, e.g. a data rate times a time is a data size.
+//
+// The zero value is the dimensionless 0.
+type Quantity struct {
+	value float64
+	dim   Dimension
+}
+
+// NewQuantity returns the Quantity of v in unit u.
+func NewQuantity(v float64, u Unit) Quantity {
+	return Quantity{v * u.Factor, u.Dim}
+}
+
+// ParseQuantity parses a number followed by an optional unit, such as "5 km",
+// "12.5 MB/s", "1.5h" or "42", where the unit is as accepted by ParseUnit.
+// Durations accepted by time.ParseDuration, such as "3h20m", are parsed as well.
+// Note that "5m" on its own is 5 meters; minutes are "min".
+func ParseQuantity(s string) (Quantity, error) {
+	t := strings.TrimSpace(s)
+	num, unit := splitNumber(t)
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return Quantity{}, fmt.Errorf("parsing quantity %q: %w", s, strconv.ErrSyntax)
+	}
+	if unit == "" {
+		return Quantity{v, Dimensionless}, nil
+	}
+
+	u, err := ParseUnit(unit)
+	if err != nil {
+		if d, err := time.ParseDuration(t); err == nil {
+			return Quantity{d.Seconds(), DimTime}, nil
+		}
+		return Quantity{}, fmt.Errorf("parsing quantity %q: unknown unit %q", s, unit)
+	}
+	return NewQuantity(v, u), nil
+}
+
+// ParseQuantityDim is like ParseQuantity, but also returns ErrDimensionMismatch
+// if the quantity is not of dimension dim, e.g. to validate a flag that
+// expects a data rate.
+func ParseQuantityDim(s string, dim Dimension) (Quantity, error) {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if q.dim != dim {
+		return Quantity{}, fmt.Errorf("parsing quantity %q: expected %s, got %s: %w",
+			s, dimName(dim), dimName(q.dim), ErrDimensionMismatch)
+	}
+	return q, nil
+}
+
+// dimName returns a name of d for error messages.
+func dimName(d Dimension) string {
+	switch d {
+	case Dimensionless:
+		return "a plain number"
+	case DimLength:
+		return "a length"
+	case DimMass:
+		return "a mass"
+	case DimTime:
+		return "a time"
+	case DimData:
+		return "a data size"
+	case DimDataRate:
+		return "a data rate"
+	}
+	return d.String()
+}
+
+// Value returns the value of q in base units, i.e. m, kg, s and B.
+func (q Quantity) Value() float64 {
+	return q.value
+}
+
+// Dim returns the dimension of q.
+func (q Quantity) Dim() Dimension {
+	return q.dim
+}
+
+// In returns the value of q in unit u. It returns ErrDimensionMismatch if u is
+// not of the dimension of q.
+func (q Quantity) In(u Unit) (float64, error) {
+	if q.dim != u.Dim {
+		return 0, ErrDimensionMismatch
+	}
+	return q.value / u.Factor, nil
+}
+
+// Add returns q+o. It returns ErrDimensionMismatch if they are not of the same
+// dimension.
+func (q Quantity) Add(o Quantity) (Quantity, error) {
+	if q.dim != o.dim {
+		return Quantity{}, ErrDimensionMismatch
+	}
+	return Quantity{q.value + o.value, q.dim}, nil
+}
+
+// Sub returns q-o. It returns ErrDimensionMismatch if they are not of the same
+// dimension.
+func (q Quantity) Sub(o Quantity) (Quantity, error) {
+	if q.dim != o.dim {
+		return Quantity{}, ErrDimensionMismatch
+	}
+	return Quantity{q.value - o.value, q.dim}, nil
+}
+
+// Mul returns q*o, whose dimension is the product of theirs.
+func (q Quantity) Mul(o Quantity) Quantity {
+	return Quantity{q.value * o.value, q.dim.mul(o.dim)}
+}
+
+// Quo returns q/o, whose dimension is the quotient of theirs, e.g. a data size
+// divided by a time is a data rate.
+func (q Quantity) Quo(o Quantity) Quantity {
+	return Quantity{q.value / o.value, q.dim.quo(o.dim)}
+}
+
+// Scale returns q multiplied by a plain number f.
+func (q Quantity) Scale(f float64) Quantity {
+	return Quantity{q.value * f, q.dim}
+}
+
+// Cmp compares q and o, and returns -1, 0 or +1 if q is less than, equal to or
+// greater than o. It returns ErrDimensionMismatch if they are not of the same
+// dimension.
+func (q Quantity) Cmp(o Quantity) (int, error) {
+	if q.dim != o.dim {
+		return 0, ErrDimensionMismatch
+	}
+	switch {
+	case q.value < o.value:
+		return -1, nil
+	case q.value > o.value:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Duration returns q as a time.Duration, which saturates at the range of
+// time.Duration. It returns ErrDimensionMismatch if q is not a time.
+func (q Quantity) Duration() (time.Duration, error) {
+	if q.dim != DimTime {
+		return 0, ErrDimensionMismatch
+	}
+	ns := q.value * 1e9
+	switch {
+	case ns >= math.MaxInt64:
+		return math.MaxInt64, nil
+	case ns <= math.MinInt64:
+		return math.MinInt64, nil
+	}
+	return time.Duration(math.Round(ns)), nil
+}
+
+// Format returns q in unit u with the shortest representation of the value,
+// e.g. "5000 m". It returns ErrDimensionMismatch if u is not of the dimension
+// of q.
+func (q Quantity) Format(u Unit) (string, error) {
+	v, err := q.In(u)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64) + " " + u.Symbol, nil
+}
+
+// String returns q with 3 significant digits and the SI prefix that gives an
+// integer part in [1, 1000), like FormatSI, e.g. "5 km", "250 µs", "12.5 MB/s"
+// or "1.5 kg". Data sizes and rates below 1 have no prefix, e.g. "0.5 B".
+// Times of a minute or longer are formatted like time.Duration instead, rounded
+// to seconds, e.g. "3h20m", except that whole minutes under an hour are
+// "20min", since "20m" is meters. Dimensions other than length, mass, time,
+// data size and data rate are formatted in base units without prefixes.
+func (q Quantity) String() string {
+	switch q.dim {
+	case Dimensionless:
+		return strconv.FormatFloat(q.value, 'g', -1, 64)
+	case DimLength:
+		return FormatSI(q.value, "m")
+	case DimMass:
+		return FormatSI(q.value*1000, "g")
+	case DimTime:
+		return formatTime(q.value)
+	case DimData:
+		return formatData(q.value, "B")
+	case DimDataRate:
+		return formatData(q.value, "B/s")
+	}
+	return strconv.FormatFloat(q.value, 'g', -1, 64) + " " + q.dim.String()
+}
+
+// formatTime formats a time of sec seconds.
+func formatTime(sec float64) string {
+	// about 292 years, the range of time.Duration
+	if a := math.Abs(sec); a < 60 || !(a < math.MaxInt64/1e9) {
+		return FormatSI(sec, "s")
+	}
+
+	// e.g. "3h20m0s" to "3h20m", and "2h0m0s" to "2h". Whole minutes without
+	// hours become "20min" rather than "20m", which would be meters.
+	s := time.Duration(math.Round(sec) * 1e9).String()
+	if strings.HasSuffix(s, "m0s") {
+		if !strings.Contains(s, "h") {
+			return s[:len(s)-3] + "min"
+		}
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// formatData is like FormatSI, but without the prefixes below 1, which data
+// units do not have, e.g. "0.5 B" rather than "500 mB".
+func formatData(v float64, unit string) string {
+	if a := math.Abs(v); a > 0 && a < 1 {
+		return formatScaled(v, 1000, []string{unit})
+	}
+	return FormatSI(v, unit)
+}
+
+// MarshalText implements encoding.TextMarshaler. Unlike String, it is exact,
+// with the value in base units, e.g. "5000 m" or "1.5e+06 B/s".
+func (q Quantity) MarshalText() ([]byte, error) {
+	s := strconv.FormatFloat(q.value, 'g', -1, 64)
+	if q.dim != Dimensionless {
+		s += " " + q.dim.String()
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseQuantity, so a
+// *Quantity can be used with flag.TextVar, for example. Only quantities in
+// units that ParseUnit accepts can be unmarshaled, which include everything
+// MarshalText returns for lengths, masses, times, data sizes and data rates.
+func (q *Quantity) UnmarshalText(text []byte) error {
+	v, err := ParseQuantity(string(text))
+	if err != nil {
+		return err
+	}
+	*q = v
+	return nil
+}
@@ -0,0 +1,75 @@
+package number
+
+import "testing"
+
+func TestQuantityStringTime(t *testing.T) {
+	tests := []struct {
+		sec  float64
+		want string
+	}{
+		{0.25, "250 ms"},
+		{59, "59 s"},
+		{60, "1min"},
+		{90, "1m30s"},
+		{1200, "20min"},
+		{-1200, "-20min"},
+		{3600, "1h"},
+		{3660, "1h1m"},
+		{12000, "3h20m"},
+		{12001, "3h20m1s"},
+		{86400, "24h"},
+	}
+
+	for _, tt := range tests {
+		q := NewQuantity(tt.sec, MustParseUnit("s"))
+		s := q.String()
+		if s != tt.want {
+			t.Errorf("String() of %v s = %q, want %q", tt.sec, s, tt.want)
+		}
+
+		back, err := ParseQuantity(s)
+		if err != nil {
+			t.Errorf("ParseQuantity(%q): %v", s, err)
+			continue
+		}
+		if back.Dim() != DimTime || back.Value() != tt.sec {
+			t.Errorf("ParseQuantity(%q) = %v %v, want %v s", s, back.Value(), back.Dim(), tt.sec)
+		}
+	}
+}
+
+func TestQuantityStringData(t *testing.T) {
+	tests := []struct {
+		q    string
+		want string
+	}{
+		{"0.5 B", "0.5 B"},
+		{"1 bit", "0.125 B"},
+		{"1 bit/s", "0.125 B/s"},
+		{"-0.25 B/s", "-0.25 B/s"},
+		{"0 B", "0 B"},
+		{"1 B", "1 B"},
+		{"1500 B", "1.5 kB"},
+		{"12.5 MB/s", "12.5 MB/s"},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuantity(tt.q)
+		if err != nil {
+			t.Errorf("ParseQuantity(%q): %v", tt.q, err)
+			continue
+		}
+		s := q.String()
+		if s != tt.want {
+			t.Errorf("String() of %s = %q, want %q", tt.q, s, tt.want)
+		}
+		back, err := ParseQuantity(s)
+		if err != nil {
+			t.Errorf("ParseQuantity(%q): %v", s, err)
+			continue
+		}
+		if back.Dim() != q.Dim() || back.Value() != q.Value() {
+			t.Errorf("ParseQuantity(%q) = %v, want %v", s, back, q)
+		}
+	}
+}